@@ -0,0 +1,110 @@
+package taskforceai
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// ListTaskArtifacts retrieves the files produced by a task. Each artifact's
+// FileID can be used with every file method.
+func (c *Client) ListTaskArtifacts(ctx context.Context, taskID string) ([]TaskArtifact, error) {
+	path := "/tasks/" + taskID + "/artifacts"
+
+	result, err := do[FileListResponse](ctx, c, "GET", path, nil, "list task artifacts")
+	if err != nil {
+		return nil, err
+	}
+
+	artifacts := make([]TaskArtifact, 0, len(result.Files))
+	for _, f := range result.Files {
+		artifacts = append(artifacts, TaskArtifact{FileID: f.ID, Filename: f.Filename, MimeType: f.MimeType, Bytes: f.Bytes})
+	}
+	return artifacts, nil
+}
+
+// DownloadTaskArtifact downloads the content of a task artifact.
+func (c *Client) DownloadTaskArtifact(ctx context.Context, artifact TaskArtifact, opts *DownloadOptions) (io.ReadCloser, error) {
+	if artifact.FileID == "" {
+		return nil, fmt.Errorf("artifact file ID is required")
+	}
+	return c.DownloadFileWithOptions(ctx, artifact.FileID, opts)
+}
+
+// SaveTaskArtifacts downloads every artifact of a completed task into dir,
+// creating it if needed, and returns the paths written. Each file is
+// verified against its metadata (see DownloadFileTo). Artifacts with the
+// same name are disambiguated with their file ID.
+func (c *Client) SaveTaskArtifacts(ctx context.Context, taskID, dir string) ([]string, error) {
+	status, err := c.GetTaskStatus(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	if status.Status != "completed" {
+		return nil, fmt.Errorf("task %s is not completed (status %q)", taskID, status.Status)
+	}
+
+	artifacts, err := c.ListTaskArtifacts(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	used := map[string]bool{}
+	paths := make([]string, 0, len(artifacts))
+	for _, artifact := range artifacts {
+		name := uniqueArtifactName(artifactFilename(artifact), safeBaseName(artifact.FileID), used)
+		used[name] = true
+
+		path := filepath.Join(dir, name)
+		if _, err := c.DownloadFileTo(ctx, artifact.FileID, path, nil); err != nil {
+			return paths, fmt.Errorf("failed to save artifact %s: %w", artifact.FileID, err)
+		}
+		paths = append(paths, path)
+	}
+
+	return paths, nil
+}
+
+// artifactFilename returns a safe local base name for an artifact.
+func artifactFilename(a TaskArtifact) string {
+	if name := safeBaseName(a.Filename); name != "" {
+		return name
+	}
+	if name := safeBaseName(a.FileID); name != "" {
+		return name
+	}
+	return "artifact"
+}
+
+// safeBaseName returns the last element of a slash-separated name, or "" if
+// there is none. The result cannot refer to a parent directory.
+func safeBaseName(name string) string {
+	base := filepath.Base(filepath.Clean("/" + filepath.FromSlash(name)))
+	if base == "." || base == string(filepath.Separator) {
+		return ""
+	}
+	return base
+}
+
+// uniqueArtifactName returns name, or name with fileID and then a counter
+// inserted before the extension, whichever is not in used.
+func uniqueArtifactName(name, fileID string, used map[string]bool) string {
+	if !used[name] {
+		return name
+	}
+	ext := filepath.Ext(name)
+	base := strings.TrimSuffix(name, ext) + "-" + fileID
+	candidate := base + ext
+	for i := 2; used[candidate]; i++ {
+		candidate = base + "-" + strconv.Itoa(i) + ext
+	}
+	return candidate
+}
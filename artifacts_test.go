@@ -0,0 +1,116 @@
+package taskforceai
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestArtifactFilename(t *testing.T) {
+	tests := []struct {
+		artifact TaskArtifact
+		want     string
+	}{
+		{TaskArtifact{FileID: "file-1", Filename: "report.pdf"}, "report.pdf"},
+		{TaskArtifact{FileID: "file-1", Filename: "../../etc/passwd"}, "passwd"},
+		{TaskArtifact{FileID: "file-1", Filename: "out/charts/q3.png"}, "q3.png"},
+		{TaskArtifact{FileID: "file-1", Filename: ".."}, "file-1"},
+		{TaskArtifact{FileID: "../x", Filename: ""}, "x"},
+		{TaskArtifact{FileID: "", Filename: "/"}, "artifact"},
+	}
+	for _, tt := range tests {
+		if got := artifactFilename(tt.artifact); got != tt.want {
+			t.Errorf("artifactFilename(%+v) = %q, want %q", tt.artifact, got, tt.want)
+		}
+	}
+}
+
+func TestUniqueArtifactName(t *testing.T) {
+	used := map[string]bool{"a.txt": true, "a-f2.txt": true}
+	if got := uniqueArtifactName("b.txt", "f1", used); got != "b.txt" {
+		t.Errorf("expected free name to be kept, got %q", got)
+	}
+	if got := uniqueArtifactName("a.txt", "f1", used); got != "a-f1.txt" {
+		t.Errorf("expected ID suffix, got %q", got)
+	}
+	// "a-f2.txt" is itself taken by an artifact literally named that.
+	if got := uniqueArtifactName("a.txt", "f2", used); got != "a-f2-2.txt" {
+		t.Errorf("expected counter suffix, got %q", got)
+	}
+}
+
+func TestClient_SaveTaskArtifacts(t *testing.T) {
+	contents := map[string]string{"f1": "first", "f2": "second", "f3": "third"}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/status/task-1":
+			_, _ = w.Write([]byte(`{"taskId": "task-1", "status": "completed"}`))
+		case r.URL.Path == "/tasks/task-1/artifacts":
+			_, _ = w.Write([]byte(`{"files": [
+				{"id": "f1", "filename": "../report.txt", "bytes": 5},
+				{"id": "f2", "filename": "report.txt", "bytes": 6},
+				{"id": "f3", "filename": "report-f2.txt", "bytes": 5}]}`))
+		case strings.HasSuffix(r.URL.Path, "/content"):
+			id := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/files/"), "/content")
+			_, _ = w.Write([]byte(contents[id]))
+		case strings.HasPrefix(r.URL.Path, "/files/"):
+			id := strings.TrimPrefix(r.URL.Path, "/files/")
+			_, _ = w.Write([]byte(`{"id": "` + id + `", "bytes": ` + strconv.Itoa(len(contents[id])) + `}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	client := NewClient(TaskForceAIOptions{BaseURL: server.URL})
+	paths, err := client.SaveTaskArtifacts(context.Background(), "task-1", dir)
+	if err != nil {
+		t.Fatalf("SaveTaskArtifacts failed: %v", err)
+	}
+
+	want := map[string]string{"report.txt": "first", "report-f2.txt": "second", "report-f2-f3.txt": "third"}
+	if len(paths) != len(want) {
+		t.Fatalf("unexpected paths %v", paths)
+	}
+	for _, path := range paths {
+		if filepath.Dir(path) != dir {
+			t.Errorf("artifact written outside dir: %s", path)
+		}
+		got, _ := os.ReadFile(path)
+		if string(got) != want[filepath.Base(path)] {
+			t.Errorf("%s = %q, want %q", filepath.Base(path), got, want[filepath.Base(path)])
+		}
+	}
+}
+
+func TestClient_DownloadTaskArtifact(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/files/f1/content" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		_, _ = w.Write([]byte("chart"))
+	}))
+	defer server.Close()
+	client := NewClient(TaskForceAIOptions{BaseURL: server.URL})
+
+	rc, err := client.DownloadTaskArtifact(context.Background(), TaskArtifact{FileID: "f1", Filename: "chart.png"}, nil)
+	if err != nil {
+		t.Fatalf("DownloadTaskArtifact failed: %v", err)
+	}
+	defer func() { _ = rc.Close() }()
+	if got, _ := io.ReadAll(rc); !bytes.Equal(got, []byte("chart")) {
+		t.Errorf("unexpected content %q", got)
+	}
+
+	if _, err := client.DownloadTaskArtifact(context.Background(), TaskArtifact{}, nil); err == nil {
+		t.Error("expected error for missing file ID")
+	}
+}
@@ -0,0 +1,144 @@
+package taskforceai
+
+import (
+	"context"
+	"fmt"
+	"mime"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// AttachmentRole tells the model how to use an attached file.
+type AttachmentRole string
+
+const (
+	AttachmentRoleContext AttachmentRole = "context" // reference material for the prompt
+	AttachmentRoleImage   AttachmentRole = "image"   // image input
+	AttachmentRoleData    AttachmentRole = "data"    // structured data to analyze
+)
+
+// Attachment references an uploaded file from a task, thread run or message.
+type Attachment struct {
+	FileID string         `json:"fileId"`
+	Role   AttachmentRole `json:"role,omitempty"`
+}
+
+// LocalAttachment is a local file to upload and attach. An empty Role is
+// inferred from the file's MIME type.
+type LocalAttachment struct {
+	Path string
+	Role AttachmentRole
+}
+
+// UploadAttachments uploads local files and returns attachments referencing
+// them. opts applies to every upload; a MIME type is derived from each file
+// extension when opts does not set one. If an upload fails, the files already
+// uploaded are deleted.
+func (c *Client) UploadAttachments(ctx context.Context, files []LocalAttachment, opts *FileUploadOptions) ([]Attachment, error) {
+	attachments := make([]Attachment, 0, len(files))
+	for _, lf := range files {
+		attachment, err := c.uploadAttachment(ctx, lf, opts)
+		if err != nil {
+			c.deleteAttachments(context.WithoutCancel(ctx), attachments)
+			return nil, fmt.Errorf("failed to attach %s: %w", lf.Path, err)
+		}
+		attachments = append(attachments, attachment)
+	}
+	return attachments, nil
+}
+
+// SubmitTaskWithFiles uploads local files, attaches them to the task and
+// submits it. The uploaded files are deleted again if the submission fails.
+func (c *Client) SubmitTaskWithFiles(ctx context.Context, prompt string, files []LocalAttachment, opts *TaskSubmissionOptions) (string, error) {
+	if prompt == "" {
+		return "", fmt.Errorf("prompt is required")
+	}
+
+	attachments, err := c.UploadAttachments(ctx, files, nil)
+	if err != nil {
+		return "", err
+	}
+
+	o := TaskSubmissionOptions{}
+	if opts != nil {
+		o = *opts
+	}
+	o.Attachments = append(append([]Attachment{}, o.Attachments...), attachments...)
+
+	taskID, err := c.SubmitTask(ctx, prompt, &o)
+	if err != nil {
+		c.deleteAttachments(context.WithoutCancel(ctx), attachments)
+		return "", err
+	}
+	return taskID, nil
+}
+
+// RunInThreadWithFiles uploads local files, attaches them to the run and
+// submits the prompt within the thread. The uploaded files are deleted again
+// if the run cannot be started.
+func (c *Client) RunInThreadWithFiles(ctx context.Context, threadID int, files []LocalAttachment, opts ThreadRunOptions) (*ThreadRunResponse, error) {
+	if opts.Prompt == "" {
+		return nil, fmt.Errorf("prompt is required")
+	}
+
+	attachments, err := c.UploadAttachments(ctx, files, nil)
+	if err != nil {
+		return nil, err
+	}
+	opts.Attachments = append(append([]Attachment{}, opts.Attachments...), attachments...)
+
+	run, err := c.RunInThread(ctx, threadID, opts)
+	if err != nil {
+		c.deleteAttachments(context.WithoutCancel(ctx), attachments)
+		return nil, err
+	}
+	return run, nil
+}
+
+// deleteAttachments removes uploaded attachment files on a best-effort basis.
+func (c *Client) deleteAttachments(ctx context.Context, attachments []Attachment) {
+	for _, a := range attachments {
+		_ = c.DeleteFile(ctx, a.FileID)
+	}
+}
+
+func (c *Client) uploadAttachment(ctx context.Context, lf LocalAttachment, opts *FileUploadOptions) (Attachment, error) {
+	f, err := os.Open(lf.Path)
+	if err != nil {
+		return Attachment{}, err
+	}
+	defer func() { _ = f.Close() }()
+
+	uploadOpts := FileUploadOptions{}
+	if opts != nil {
+		uploadOpts = *opts
+	}
+	if uploadOpts.MimeType == "" {
+		uploadOpts.MimeType = mime.TypeByExtension(filepath.Ext(lf.Path))
+	}
+
+	file, err := c.UploadFile(ctx, filepath.Base(lf.Path), f, &uploadOpts)
+	if err != nil {
+		return Attachment{}, err
+	}
+
+	role := lf.Role
+	if role == "" {
+		role = inferAttachmentRole(uploadOpts.MimeType)
+	}
+	return Attachment{FileID: file.ID, Role: role}, nil
+}
+
+func inferAttachmentRole(mimeType string) AttachmentRole {
+	mediaType, _, _ := mime.ParseMediaType(mimeType)
+	switch {
+	case strings.HasPrefix(mediaType, "image/"):
+		return AttachmentRoleImage
+	case mediaType == "text/csv", mediaType == "application/json", strings.HasSuffix(mediaType, "+json"),
+		mediaType == "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet":
+		return AttachmentRoleData
+	default:
+		return AttachmentRoleContext
+	}
+}
@@ -0,0 +1,135 @@
+package taskforceai
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestClient_SubmitTaskWithFiles(t *testing.T) {
+	var got map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "POST" && r.URL.Path == "/files":
+			_, _ = w.Write([]byte(`{"id": "file-1", "filename": "sales.csv"}`))
+		case r.Method == "POST" && r.URL.Path == "/run":
+			_ = json.NewDecoder(r.Body).Decode(&got)
+			_, _ = w.Write([]byte(`{"taskId": "task-1"}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "sales.csv")
+	if err := os.WriteFile(path, []byte("month,total\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	client := NewClient(TaskForceAIOptions{BaseURL: server.URL})
+	taskID, err := client.SubmitTaskWithFiles(context.Background(), "Summarize", []LocalAttachment{{Path: path}}, nil)
+	if err != nil || taskID != "task-1" {
+		t.Fatalf("SubmitTaskWithFiles = %q, %v", taskID, err)
+	}
+
+	options, _ := got["options"].(map[string]any)
+	attachments, _ := options["attachments"].([]any)
+	if len(attachments) != 1 {
+		t.Fatalf("unexpected options %v", options)
+	}
+	attachment := attachments[0].(map[string]any)
+	if attachment["fileId"] != "file-1" || attachment["role"] != "data" || len(attachment) != 2 {
+		t.Errorf("unexpected attachment %v", attachment)
+	}
+}
+
+func TestClient_UploadAttachments_CleansUpOnFailure(t *testing.T) {
+	var mu sync.Mutex
+	var deleted []string
+	uploads := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch {
+		case r.Method == "POST" && r.URL.Path == "/files":
+			uploads++
+			if uploads == 3 {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			_, _ = w.Write([]byte(`{"id": "file-` + strconv.Itoa(uploads) + `"}`))
+		case r.Method == "DELETE":
+			deleted = append(deleted, strings.TrimPrefix(r.URL.Path, "/files/"))
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	var files []LocalAttachment
+	for _, name := range []string{"a.txt", "b.txt", "c.txt"} {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(name), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		files = append(files, LocalAttachment{Path: path})
+	}
+
+	client := NewClient(TaskForceAIOptions{BaseURL: server.URL})
+	attachments, err := client.UploadAttachments(context.Background(), files, nil)
+	if err == nil || !strings.Contains(err.Error(), "c.txt") || attachments != nil {
+		t.Fatalf("expected error for c.txt, got %v, %v", attachments, err)
+	}
+	if len(deleted) != 2 || deleted[0] != "file-1" || deleted[1] != "file-2" {
+		t.Errorf("expected earlier uploads to be deleted, got %v", deleted)
+	}
+}
+
+func TestClient_WithFiles_CleansUpOnSubmitFailure(t *testing.T) {
+	var mu sync.Mutex
+	var deleted []string
+	uploads := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch {
+		case r.Method == "POST" && r.URL.Path == "/files":
+			uploads++
+			_, _ = w.Write([]byte(`{"id": "file-` + strconv.Itoa(uploads) + `"}`))
+		case r.Method == "POST" && (r.URL.Path == "/run" || r.URL.Path == "/threads/4/runs"):
+			http.Error(w, `{"error": "quota exceeded"}`, http.StatusTooManyRequests)
+		case r.Method == "DELETE":
+			deleted = append(deleted, strings.TrimPrefix(r.URL.Path, "/files/"))
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "notes.txt")
+	if err := os.WriteFile(path, []byte("notes"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	files := []LocalAttachment{{Path: path}}
+	client := NewClient(TaskForceAIOptions{BaseURL: server.URL})
+
+	if _, err := client.SubmitTaskWithFiles(context.Background(), "Summarize", files, nil); !isStatus(err, http.StatusTooManyRequests) {
+		t.Errorf("expected submit error, got %v", err)
+	}
+	if _, err := client.RunInThreadWithFiles(context.Background(), 4, files, ThreadRunOptions{Prompt: "Summarize"}); !isStatus(err, http.StatusTooManyRequests) {
+		t.Errorf("expected run error, got %v", err)
+	}
+	if len(deleted) != 2 || deleted[0] != "file-1" || deleted[1] != "file-2" {
+		t.Errorf("expected both uploads to be deleted, got %v", deleted)
+	}
+}
@@ -0,0 +1,170 @@
+package taskforceai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// ChatMessage is a message in the common OpenAI-style chat format,
+// {"role": ..., "content": ...}.
+type ChatMessage struct {
+	Role    string      `json:"role"`
+	Content ChatContent `json:"content"`
+	Name    string      `json:"name,omitempty"`
+}
+
+// ChatContent is the content of a ChatMessage: either a plain string or,
+// when Parts is non-nil, an array of content parts.
+type ChatContent struct {
+	Text  string
+	Parts []ChatContentPart
+}
+
+// ChatContentPart is one part of multi-part content.
+type ChatContentPart struct {
+	Type     string        `json:"type"` // "text", "image_url" or "file"
+	Text     string        `json:"text,omitempty"`
+	ImageURL *ChatImageURL `json:"image_url,omitempty"`
+	File     *ChatFile     `json:"file,omitempty"`
+}
+
+// ChatImageURL is the image of an "image_url" content part.
+type ChatImageURL struct {
+	URL    string `json:"url"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// ChatFile is the file of a "file" content part.
+type ChatFile struct {
+	FileID   string `json:"file_id,omitempty"`
+	Filename string `json:"filename,omitempty"`
+}
+
+// MarshalJSON encodes the content as a string, or as an array if it has
+// parts.
+func (c ChatContent) MarshalJSON() ([]byte, error) {
+	if c.Parts != nil {
+		return json.Marshal(c.Parts)
+	}
+	return json.Marshal(c.Text)
+}
+
+// UnmarshalJSON accepts a string, an array of parts or null.
+func (c *ChatContent) UnmarshalJSON(data []byte) error {
+	*c = ChatContent{}
+	data = bytes.TrimSpace(data)
+	switch {
+	case bytes.Equal(data, []byte("null")):
+		return nil
+	case len(data) > 0 && data[0] == '[':
+		c.Parts = []ChatContentPart{}
+		return json.Unmarshal(data, &c.Parts)
+	default:
+		return json.Unmarshal(data, &c.Text)
+	}
+}
+
+// ChatToThreadMessages converts chat messages to thread messages. The
+// "developer" role is treated as "system". Text parts are joined with blank
+// lines, "file" parts become attachments and http(s) "image_url" parts are
+// kept as Markdown images; other parts and roles are rejected.
+func ChatToThreadMessages(messages []ChatMessage) ([]ThreadMessage, error) {
+	result := make([]ThreadMessage, 0, len(messages))
+	for i, m := range messages {
+		tm, err := chatToThreadMessage(m)
+		if err != nil {
+			return nil, fmt.Errorf("message %d: %w", i, err)
+		}
+		result = append(result, tm)
+	}
+	return result, nil
+}
+
+// ThreadMessagesToChat converts thread messages to chat messages. Messages
+// with attachments use multi-part content with a "file" part per attachment.
+func ThreadMessagesToChat(messages []ThreadMessage) []ChatMessage {
+	result := make([]ChatMessage, 0, len(messages))
+	for _, m := range messages {
+		cm := ChatMessage{Role: m.Role, Content: ChatContent{Text: m.Content}}
+		if len(m.Attachments) > 0 {
+			parts := []ChatContentPart{}
+			if m.Content != "" {
+				parts = append(parts, ChatContentPart{Type: "text", Text: m.Content})
+			}
+			for _, a := range m.Attachments {
+				parts = append(parts, ChatContentPart{Type: "file", File: &ChatFile{FileID: a.FileID}})
+			}
+			cm.Content = ChatContent{Parts: parts}
+		}
+		result = append(result, cm)
+	}
+	return result
+}
+
+// CreateThreadFromChat creates a thread holding a chat history. Title and
+// Metadata are taken from opts; its Messages are replaced.
+func (c *Client) CreateThreadFromChat(ctx context.Context, messages []ChatMessage, opts *CreateThreadOptions) (*Thread, error) {
+	converted, err := ChatToThreadMessages(messages)
+	if err != nil {
+		return nil, err
+	}
+
+	o := CreateThreadOptions{}
+	if opts != nil {
+		o = *opts
+	}
+	o.Messages = converted
+
+	return c.CreateThread(ctx, &o)
+}
+
+// GetThreadChatMessages returns the whole history of a thread in chat
+// format.
+func (c *Client) GetThreadChatMessages(ctx context.Context, threadID int) ([]ChatMessage, error) {
+	messages, err := c.listAllThreadMessages(ctx, threadID)
+	if err != nil {
+		return nil, err
+	}
+	return ThreadMessagesToChat(messages), nil
+}
+
+func chatToThreadMessage(m ChatMessage) (ThreadMessage, error) {
+	role := m.Role
+	if role == "developer" {
+		role = MessageRoleSystem
+	}
+	if err := validateMessageRole(role); err != nil {
+		return ThreadMessage{}, err
+	}
+
+	tm := ThreadMessage{Role: role, Content: m.Content.Text}
+	if m.Content.Parts == nil {
+		return tm, nil
+	}
+
+	var texts []string
+	for _, part := range m.Content.Parts {
+		switch part.Type {
+		case "text":
+			texts = append(texts, part.Text)
+		case "file":
+			if part.File == nil || part.File.FileID == "" {
+				return ThreadMessage{}, fmt.Errorf("file part without a file ID")
+			}
+			tm.Attachments = append(tm.Attachments, Attachment{FileID: part.File.FileID})
+		case "image_url":
+			if part.ImageURL == nil || !(strings.HasPrefix(part.ImageURL.URL, "http://") || strings.HasPrefix(part.ImageURL.URL, "https://")) {
+				return ThreadMessage{}, fmt.Errorf("image_url part must be an http(s) URL; upload inline images and use a file part")
+			}
+			texts = append(texts, "![image]("+part.ImageURL.URL+")")
+		default:
+			return ThreadMessage{}, fmt.Errorf("unsupported content part type %q", part.Type)
+		}
+	}
+	tm.Content = strings.Join(texts, "\n\n")
+
+	return tm, nil
+}
@@ -0,0 +1,124 @@
+package taskforceai
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestChatConversion(t *testing.T) {
+	input := `[
+		{"role": "developer", "content": "Be brief."},
+		{"role": "user", "content": [
+			{"type": "text", "text": "Describe this."},
+			{"type": "image_url", "image_url": {"url": "https://example.com/cat.png"}},
+			{"type": "file", "file": {"file_id": "file-9"}}
+		]},
+		{"role": "assistant", "content": "A cat."}
+	]`
+
+	var chat []ChatMessage
+	if err := json.Unmarshal([]byte(input), &chat); err != nil {
+		t.Fatalf("unmarshal failed: %v", err)
+	}
+
+	messages, err := ChatToThreadMessages(chat)
+	if err != nil {
+		t.Fatalf("ChatToThreadMessages failed: %v", err)
+	}
+	if len(messages) != 3 || messages[0].Role != MessageRoleSystem || messages[0].Content != "Be brief." {
+		t.Fatalf("unexpected messages %+v", messages)
+	}
+	if m := messages[1]; m.Content != "Describe this.\n\n![image](https://example.com/cat.png)" || len(m.Attachments) != 1 || m.Attachments[0].FileID != "file-9" {
+		t.Errorf("unexpected multi-part conversion %+v", m)
+	}
+
+	back := ThreadMessagesToChat(messages)
+	data, err := json.Marshal(back)
+	if err != nil {
+		t.Fatalf("marshal failed: %v", err)
+	}
+	var decoded []map[string]any
+	_ = json.Unmarshal(data, &decoded)
+	if decoded[0]["content"] != "Be brief." {
+		t.Errorf("expected string content, got %v", decoded[0]["content"])
+	}
+	if parts, ok := decoded[1]["content"].([]any); !ok || len(parts) != 2 {
+		t.Errorf("expected text and file parts, got %v", decoded[1]["content"])
+	}
+
+	if _, err := ChatToThreadMessages([]ChatMessage{{Role: "tool", Content: ChatContent{Text: "x"}}}); err == nil {
+		t.Error("expected error for tool role")
+	}
+	inline := []ChatMessage{{Role: "user", Content: ChatContent{Parts: []ChatContentPart{{Type: "image_url", ImageURL: &ChatImageURL{URL: "data:image/png;base64,AAAA"}}}}}}
+	if _, err := ChatToThreadMessages(inline); err == nil {
+		t.Error("expected error for inline image")
+	}
+}
+
+func TestClient_CreateThreadFromChat(t *testing.T) {
+	var body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || r.URL.Path != "/threads" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		data, _ := io.ReadAll(r.Body)
+		body = string(data)
+		_ = json.NewEncoder(w).Encode(Thread{ID: 4, Title: "chat"})
+	}))
+	defer server.Close()
+	client := NewClient(TaskForceAIOptions{BaseURL: server.URL})
+
+	chat := []ChatMessage{
+		{Role: "developer", Content: ChatContent{Text: "Be brief."}},
+		{Role: "user", Content: ChatContent{Parts: []ChatContentPart{
+			{Type: "text", Text: "hi"},
+			{Type: "file", File: &ChatFile{FileID: "file-9"}},
+		}}},
+	}
+	thread, err := client.CreateThreadFromChat(context.Background(), chat, &CreateThreadOptions{
+		Title:    "chat",
+		Messages: []ThreadMessage{{Role: MessageRoleUser, Content: "replaced"}},
+	})
+	if err != nil {
+		t.Fatalf("CreateThreadFromChat failed: %v", err)
+	}
+	if thread.ID != 4 {
+		t.Errorf("unexpected thread %+v", thread)
+	}
+	want := `{"messages":[{"role":"system","content":"Be brief."},{"role":"user","content":"hi","attachments":[{"fileId":"file-9"}]}],"title":"chat"}`
+	if body != want+"\n" && body != want {
+		t.Errorf("unexpected body\n got %s\nwant %s", body, want)
+	}
+
+	if _, err := client.CreateThreadFromChat(context.Background(), []ChatMessage{{Role: "tool"}}, nil); err == nil {
+		t.Error("expected error for tool role")
+	}
+}
+
+func TestClient_GetThreadChatMessages(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/threads/4/messages" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		_ = json.NewEncoder(w).Encode(ThreadMessagesResponse{Messages: []ThreadMessage{
+			{ID: 1, ThreadID: 4, Role: MessageRoleUser, Content: "hi", Attachments: []Attachment{{FileID: "file-9"}}},
+			{ID: 2, ThreadID: 4, Role: MessageRoleAssistant, Content: "hello"},
+		}, Total: 2})
+	}))
+	defer server.Close()
+	client := NewClient(TaskForceAIOptions{BaseURL: server.URL})
+
+	chat, err := client.GetThreadChatMessages(context.Background(), 4)
+	if err != nil {
+		t.Fatalf("GetThreadChatMessages failed: %v", err)
+	}
+	data, _ := json.Marshal(chat)
+	want := `[{"role":"user","content":[{"type":"text","text":"hi"},{"type":"file","file":{"file_id":"file-9"}}]},{"role":"assistant","content":"hello"}]`
+	if string(data) != want {
+		t.Errorf("unexpected chat messages\n got %s\nwant %s", data, want)
+	}
+}
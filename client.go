@@ -8,6 +8,7 @@ import (
 	"io"
 	"net/http"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -19,12 +20,16 @@ const (
 )
 
 type Client struct {
-	apiKey       string
-	baseURL      string
-	timeout      time.Duration
-	responseHook func(statusCode int, header map[string][]string)
-	mockMode     bool
-	httpClient   *http.Client
+	apiKey           string
+	baseURL          string
+	timeout          time.Duration
+	responseHook     func(statusCode int, header map[string][]string)
+	mockMode         bool
+	maxResponseBytes int64
+	httpClient       *http.Client
+
+	// metadataMu serializes read-modify-write updates of thread metadata.
+	metadataMu sync.Mutex
 }
 
 func NewClient(opts TaskForceAIOptions) *Client {
@@ -39,12 +44,18 @@ func NewClient(opts TaskForceAIOptions) *Client {
 		timeout = DefaultTimeout
 	}
 
+	maxResponseBytes := opts.MaxResponseBytes
+	if maxResponseBytes <= 0 {
+		maxResponseBytes = DefaultMaxResponseBytes
+	}
+
 	return &Client{
-		apiKey:       opts.APIKey,
-		baseURL:      baseURL,
-		timeout:      timeout,
-		responseHook: opts.ResponseHook,
-		mockMode:     opts.MockMode,
+		apiKey:           opts.APIKey,
+		baseURL:          baseURL,
+		timeout:          timeout,
+		responseHook:     opts.ResponseHook,
+		mockMode:         opts.MockMode,
+		maxResponseBytes: maxResponseBytes,
 		httpClient: &http.Client{
 			Timeout: timeout,
 		},
@@ -52,7 +63,6 @@ func NewClient(opts TaskForceAIOptions) *Client {
 }
 
 func (c *Client) doRequest(ctx context.Context, method, path string, body interface{}) (*http.Response, error) {
-	url := c.baseURL + path
 	var bodyReader io.Reader
 	if body != nil {
 		jsonBody, err := json.Marshal(body)
@@ -62,17 +72,32 @@ func (c *Client) doRequest(ctx context.Context, method, path string, body interf
 		bodyReader = bytes.NewReader(jsonBody)
 	}
 
-	req, err := http.NewRequestWithContext(ctx, method, url, bodyReader)
+	req, err := c.newRequest(ctx, method, path, bodyReader)
 	if err != nil {
 		return nil, err
 	}
-
 	req.Header.Set("Content-Type", "application/json")
+
+	return c.send(req)
+}
+
+// newRequest builds a request against the API with the SDK's standard headers.
+func (c *Client) newRequest(ctx context.Context, method, path string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, body)
+	if err != nil {
+		return nil, err
+	}
+
 	if c.apiKey != "" {
 		req.Header.Set("Authorization", "Bearer "+c.apiKey)
 	}
 	req.Header.Set("X-SDK-Language", "go")
 
+	return req, nil
+}
+
+// send executes req and reports the response to the response hook.
+func (c *Client) send(req *http.Request) (*http.Response, error) {
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		return nil, err
@@ -85,6 +110,25 @@ func (c *Client) doRequest(ctx context.Context, method, path string, body interf
 	return resp, nil
 }
 
+// sendStreaming is send without the client's overall timeout, for responses
+// whose bodies may take longer than that to read. Callers bound the request
+// through its context instead.
+func (c *Client) sendStreaming(req *http.Request) (*http.Response, error) {
+	client := *c.httpClient
+	client.Timeout = 0
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.responseHook != nil {
+		c.responseHook(resp.StatusCode, resp.Header)
+	}
+
+	return resp, nil
+}
+
 func (c *Client) SubmitTask(ctx context.Context, prompt string, opts *TaskSubmissionOptions) (string, error) {
 	if prompt == "" {
 		return "", fmt.Errorf("prompt is required")
@@ -97,20 +141,10 @@ func (c *Client) SubmitTask(ctx context.Context, prompt string, opts *TaskSubmis
 		body["options"] = opts
 	}
 
-	resp, err := c.doRequest(ctx, "POST", "/run", body)
-	if err != nil {
-		return "", err
-	}
-	defer func() { _ = resp.Body.Close() }()
-
-	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusAccepted {
-		return "", fmt.Errorf("failed to submit task: status %d", resp.StatusCode)
-	}
-
-	var result struct {
+	result, err := do[struct {
 		TaskID string `json:"taskId"`
-	}
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+	}](ctx, c, "POST", "/run", body, "submit task")
+	if err != nil {
 		return "", err
 	}
 
@@ -118,22 +152,7 @@ func (c *Client) SubmitTask(ctx context.Context, prompt string, opts *TaskSubmis
 }
 
 func (c *Client) GetTaskStatus(ctx context.Context, taskID string) (TaskStatus, error) {
-	resp, err := c.doRequest(ctx, "GET", "/status/"+taskID, nil)
-	if err != nil {
-		return TaskStatus{}, err
-	}
-	defer func() { _ = resp.Body.Close() }()
-
-	if resp.StatusCode != http.StatusOK {
-		return TaskStatus{}, fmt.Errorf("failed to get task status: status %d", resp.StatusCode)
-	}
-
-	var status TaskStatus
-	if err := json.NewDecoder(resp.Body).Decode(&status); err != nil {
-		return TaskStatus{}, err
-	}
-
-	return status, nil
+	return do[TaskStatus](ctx, c, "GET", "/status/"+taskID, nil, "get task status")
 }
 
 func (c *Client) WaitForCompletion(ctx context.Context, taskID string, pollInterval time.Duration, maxAttempts int, callback TaskStatusCallback) (TaskStatus, error) {
@@ -0,0 +1,459 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	taskforceai "github.com/ClayWarren/taskforceai-sdk-go"
+)
+
+const (
+	benchModePoll   = "poll"
+	benchModeStream = "stream"
+)
+
+// benchConfig controls a benchmark run.
+type benchConfig struct {
+	BaseURL      string
+	APIKey       string
+	Prompt       string
+	ModelID      string
+	Mock         bool
+	Mode         string
+	Concurrency  int
+	Duration     time.Duration
+	CycleTimeout time.Duration
+	PollInterval time.Duration
+	MaxPolls     int
+}
+
+// latencySummary describes the distribution of one latency metric in milliseconds.
+type latencySummary struct {
+	Count int     `json:"count"`
+	Min   float64 `json:"min_ms"`
+	Mean  float64 `json:"mean_ms"`
+	P50   float64 `json:"p50_ms"`
+	P90   float64 `json:"p90_ms"`
+	P95   float64 `json:"p95_ms"`
+	P99   float64 `json:"p99_ms"`
+	Max   float64 `json:"max_ms"`
+}
+
+// benchReport is the summary of a benchmark run.
+type benchReport struct {
+	BaseURL          string                    `json:"base_url"`
+	Mode             string                    `json:"mode"`
+	Concurrency      int                       `json:"concurrency"`
+	Duration         float64                   `json:"duration_seconds"`
+	Elapsed          float64                   `json:"elapsed_seconds"`
+	Cycles           int                       `json:"cycles"`
+	Succeeded        int                       `json:"succeeded"`
+	Failed           int                       `json:"failed"`
+	Throughput       float64                   `json:"throughput_per_second"`
+	Latency          map[string]latencySummary `json:"latency"`
+	Errors           map[string]int            `json:"errors"`
+	StatusCodes      map[int]int               `json:"status_codes"`
+	Responses        int                       `json:"responses"`
+	RateLimited      int                       `json:"rate_limited"`
+	RateLimitedRatio float64                   `json:"rate_limited_ratio"`
+}
+
+// benchRecorder collects measurements from all workers.
+type benchRecorder struct {
+	mu          sync.Mutex
+	submit      []time.Duration
+	firstEvent  []time.Duration
+	completion  []time.Duration
+	succeeded   int
+	failed      int
+	errors      map[string]int
+	statusCodes map[int]int
+}
+
+func newBenchRecorder() *benchRecorder {
+	return &benchRecorder{
+		errors:      map[string]int{},
+		statusCodes: map[int]int{},
+	}
+}
+
+func (r *benchRecorder) recordStatus(code int) {
+	r.mu.Lock()
+	r.statusCodes[code]++
+	r.mu.Unlock()
+}
+
+func (r *benchRecorder) recordCycle(res cycleResult) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if res.submit > 0 {
+		r.submit = append(r.submit, res.submit)
+	}
+	if res.firstEvent > 0 {
+		r.firstEvent = append(r.firstEvent, res.firstEvent)
+	}
+	if res.errKind != "" {
+		r.failed++
+		r.errors[res.errKind]++
+		return
+	}
+	r.succeeded++
+	r.completion = append(r.completion, res.completion)
+}
+
+// cycleResult holds the timings of one submit+wait cycle. Durations are
+// measured from the start of the cycle; a zero value means the phase was
+// never reached.
+type cycleResult struct {
+	submit     time.Duration
+	firstEvent time.Duration
+	completion time.Duration
+	errKind    string
+}
+
+// benchWorker runs cycles sequentially with its own client so that the
+// response hook can attribute failures to the status code that caused them.
+type benchWorker struct {
+	cfg        benchConfig
+	client     *taskforceai.Client
+	rec        *benchRecorder
+	lastStatus int
+}
+
+func newBenchWorker(cfg benchConfig, rec *benchRecorder) *benchWorker {
+	w := &benchWorker{cfg: cfg, rec: rec}
+	// The client timeout also bounds reading a stream, so it follows the
+	// cycle timeout rather than cutting long streamed tasks short.
+	w.client = taskforceai.NewClient(taskforceai.TaskForceAIOptions{
+		APIKey:  cfg.APIKey,
+		BaseURL: cfg.BaseURL,
+		Timeout: cfg.CycleTimeout,
+		ResponseHook: func(statusCode int, header map[string][]string) {
+			w.lastStatus = statusCode
+			rec.recordStatus(statusCode)
+		},
+	})
+	return w
+}
+
+func (w *benchWorker) cycle(ctx context.Context) cycleResult {
+	var res cycleResult
+
+	if w.cfg.CycleTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, w.cfg.CycleTimeout)
+		defer cancel()
+	}
+
+	var opts *taskforceai.TaskSubmissionOptions
+	if w.cfg.ModelID != "" || w.cfg.Mock {
+		opts = &taskforceai.TaskSubmissionOptions{ModelID: w.cfg.ModelID, Mock: w.cfg.Mock}
+	}
+
+	start := time.Now()
+	w.lastStatus = 0
+	taskID, err := w.client.SubmitTask(ctx, w.cfg.Prompt, opts)
+	if err != nil {
+		res.errKind = w.classify(ctx, err)
+		return res
+	}
+	res.submit = time.Since(start)
+
+	if w.cfg.Mode == benchModeStream {
+		err = w.stream(ctx, taskID, start, &res)
+	} else {
+		err = w.poll(ctx, taskID, start, &res)
+	}
+	if err != nil {
+		res.errKind = w.classify(ctx, err)
+		return res
+	}
+	res.completion = time.Since(start)
+	return res
+}
+
+func (w *benchWorker) poll(ctx context.Context, taskID string, start time.Time, res *cycleResult) error {
+	w.lastStatus = 0
+	_, err := w.client.WaitForCompletion(ctx, taskID, w.cfg.PollInterval, w.cfg.MaxPolls, func(status taskforceai.TaskStatus) {
+		if res.firstEvent == 0 {
+			res.firstEvent = time.Since(start)
+		}
+	})
+	return err
+}
+
+func (w *benchWorker) stream(ctx context.Context, taskID string, start time.Time, res *cycleResult) error {
+	w.lastStatus = 0
+	stream, err := w.client.StreamTaskStatus(ctx, taskID)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = stream.Close() }()
+
+	for {
+		status, err := stream.Next()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return errStreamEnded
+			}
+			return err
+		}
+		if res.firstEvent == 0 {
+			res.firstEvent = time.Since(start)
+		}
+		switch status.Status {
+		case "completed":
+			return nil
+		case "failed":
+			return errTaskFailed
+		}
+	}
+}
+
+var (
+	errTaskFailed  = errors.New("task failed")
+	errStreamEnded = errors.New("stream ended before completion")
+)
+
+// classify maps a cycle error to the bucket it is reported under.
+func (w *benchWorker) classify(ctx context.Context, err error) string {
+	switch {
+	case errors.Is(err, errTaskFailed) || strings.HasPrefix(err.Error(), "task failed"):
+		return "task_failed"
+	case errors.Is(err, errStreamEnded):
+		return "stream_ended"
+	case err.Error() == "task timed out":
+		return "poll_exhausted"
+	case w.lastStatus >= 400:
+		return fmt.Sprintf("http_%d", w.lastStatus)
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		return "timeout"
+	case errors.Is(err, context.Canceled):
+		return "canceled"
+	default:
+		return "transport"
+	}
+}
+
+// runBench drives cfg.Concurrency workers until cfg.Duration has elapsed or
+// ctx is cancelled. Cycles already in flight when the duration expires are
+// allowed to finish; cycles interrupted by ctx are discarded.
+func runBench(ctx context.Context, cfg benchConfig) *benchReport {
+	rec := newBenchRecorder()
+	start := time.Now()
+	deadline := start.Add(cfg.Duration)
+
+	var wg sync.WaitGroup
+	for i := 0; i < cfg.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			w := newBenchWorker(cfg, rec)
+			for ctx.Err() == nil && time.Now().Before(deadline) {
+				res := w.cycle(ctx)
+				if ctx.Err() != nil {
+					return
+				}
+				rec.recordCycle(res)
+			}
+		}()
+	}
+	wg.Wait()
+
+	return rec.report(cfg, time.Since(start))
+}
+
+func (r *benchRecorder) report(cfg benchConfig, elapsed time.Duration) *benchReport {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	report := &benchReport{
+		BaseURL:     cfg.BaseURL,
+		Mode:        cfg.Mode,
+		Concurrency: cfg.Concurrency,
+		Duration:    cfg.Duration.Seconds(),
+		Elapsed:     elapsed.Seconds(),
+		Cycles:      r.succeeded + r.failed,
+		Succeeded:   r.succeeded,
+		Failed:      r.failed,
+		Latency: map[string]latencySummary{
+			"submit":      summarize(r.submit),
+			"first_event": summarize(r.firstEvent),
+			"completion":  summarize(r.completion),
+		},
+		Errors:      map[string]int{},
+		StatusCodes: map[int]int{},
+	}
+	if elapsed > 0 {
+		report.Throughput = float64(r.succeeded) / elapsed.Seconds()
+	}
+	for k, v := range r.errors {
+		report.Errors[k] = v
+	}
+	for code, n := range r.statusCodes {
+		report.StatusCodes[code] = n
+		report.Responses += n
+	}
+	report.RateLimited = r.statusCodes[429]
+	if report.Responses > 0 {
+		report.RateLimitedRatio = float64(report.RateLimited) / float64(report.Responses)
+	}
+	return report
+}
+
+func summarize(samples []time.Duration) latencySummary {
+	if len(samples) == 0 {
+		return latencySummary{}
+	}
+
+	sorted := make([]time.Duration, len(samples))
+	copy(sorted, samples)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	var total time.Duration
+	for _, d := range sorted {
+		total += d
+	}
+
+	return latencySummary{
+		Count: len(sorted),
+		Min:   millis(sorted[0]),
+		Mean:  millis(total / time.Duration(len(sorted))),
+		P50:   millis(percentile(sorted, 50)),
+		P90:   millis(percentile(sorted, 90)),
+		P95:   millis(percentile(sorted, 95)),
+		P99:   millis(percentile(sorted, 99)),
+		Max:   millis(sorted[len(sorted)-1]),
+	}
+}
+
+// percentile returns the nearest-rank percentile of an ascending slice.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+func millis(d time.Duration) float64 {
+	return math.Round(float64(d)/float64(time.Microsecond)) / 1000
+}
+
+func writeBenchText(w io.Writer, r *benchReport) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+
+	fmt.Fprintf(w, "Benchmark: %s mode, %d workers, %s against %s\n",
+		r.Mode, r.Concurrency, time.Duration(r.Duration*float64(time.Second)), r.BaseURL)
+	fmt.Fprintf(w, "Cycles:     %d (%d succeeded, %d failed) in %.1fs\n", r.Cycles, r.Succeeded, r.Failed, r.Elapsed)
+	fmt.Fprintf(w, "Throughput: %.2f completed tasks/s\n\n", r.Throughput)
+
+	fmt.Fprintln(tw, "latency (ms)\tcount\tmin\tmean\tp50\tp90\tp95\tp99\tmax\t")
+	for _, name := range []string{"submit", "first_event", "completion"} {
+		s := r.Latency[name]
+		fmt.Fprintf(tw, "%s\t%d\t%.1f\t%.1f\t%.1f\t%.1f\t%.1f\t%.1f\t%.1f\t\n",
+			name, s.Count, s.Min, s.Mean, s.P50, s.P90, s.P95, s.P99, s.Max)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(w, "\nHTTP responses: %d, rate limited (429): %d (%.2f%%)\n",
+		r.Responses, r.RateLimited, r.RateLimitedRatio*100)
+	if len(r.StatusCodes) > 0 {
+		codes := make([]int, 0, len(r.StatusCodes))
+		for code := range r.StatusCodes {
+			codes = append(codes, code)
+		}
+		sort.Ints(codes)
+		parts := make([]string, 0, len(codes))
+		for _, code := range codes {
+			parts = append(parts, fmt.Sprintf("%d=%d", code, r.StatusCodes[code]))
+		}
+		fmt.Fprintf(w, "Status codes:   %s\n", strings.Join(parts, " "))
+	}
+
+	if len(r.Errors) > 0 {
+		kinds := make([]string, 0, len(r.Errors))
+		for kind := range r.Errors {
+			kinds = append(kinds, kind)
+		}
+		sort.Strings(kinds)
+		fmt.Fprintln(w, "Errors:")
+		for _, kind := range kinds {
+			fmt.Fprintf(w, "  %-16s %d\n", kind, r.Errors[kind])
+		}
+	}
+	return nil
+}
+
+func runBenchCommand(args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("bench", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+
+	cfg := benchConfig{}
+	fs.StringVar(&cfg.BaseURL, "base-url", taskforceai.DefaultBaseURL, "API base URL")
+	fs.StringVar(&cfg.APIKey, "api-key", "", "API key (default $TASKFORCEAI_API_KEY)")
+	fs.StringVar(&cfg.Prompt, "prompt", "Reply with the single word: pong", "prompt submitted in every cycle")
+	fs.StringVar(&cfg.ModelID, "model", "", "model ID to submit tasks with")
+	fs.BoolVar(&cfg.Mock, "mock", false, "submit tasks in server-side mock mode")
+	fs.StringVar(&cfg.Mode, "mode", benchModePoll, "wait strategy: poll or stream")
+	fs.IntVar(&cfg.Concurrency, "c", 4, "number of concurrent workers")
+	fs.DurationVar(&cfg.Duration, "d", 30*time.Second, "how long to keep starting new cycles")
+	fs.DurationVar(&cfg.CycleTimeout, "cycle-timeout", 2*time.Minute, "maximum duration of a single cycle, also used as the HTTP client timeout")
+	fs.DurationVar(&cfg.PollInterval, "poll-interval", taskforceai.DefaultPollInterval, "status poll interval in poll mode")
+	fs.IntVar(&cfg.MaxPolls, "max-polls", taskforceai.DefaultMaxPoll, "maximum status polls per cycle in poll mode")
+	jsonOut := fs.Bool("json", false, "print the summary as JSON instead of text")
+	jsonFile := fs.String("json-file", "", "also write the JSON summary to this file")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if cfg.APIKey == "" {
+		cfg.APIKey = apiKeyFromEnv()
+	}
+	if cfg.Mode != benchModePoll && cfg.Mode != benchModeStream {
+		return fmt.Errorf("unknown mode %q (want %s or %s)", cfg.Mode, benchModePoll, benchModeStream)
+	}
+	if cfg.Concurrency < 1 {
+		return fmt.Errorf("concurrency must be at least 1")
+	}
+	if cfg.Duration <= 0 {
+		return fmt.Errorf("duration must be positive")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	report := runBench(ctx, cfg)
+
+	if *jsonFile != "" {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(*jsonFile, append(data, '\n'), 0o644); err != nil {
+			return err
+		}
+	}
+
+	if *jsonOut {
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(report)
+	}
+	return writeBenchText(stdout, report)
+}
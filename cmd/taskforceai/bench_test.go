@@ -0,0 +1,101 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRunBench_Poll(t *testing.T) {
+	var submits int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/run" {
+			if atomic.AddInt32(&submits, 1)%3 == 0 {
+				w.WriteHeader(http.StatusTooManyRequests)
+				return
+			}
+			_, _ = w.Write([]byte(`{"taskId": "t"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"taskId": "t", "status": "completed", "result": "pong"}`))
+	}))
+	defer server.Close()
+
+	report := runBench(context.Background(), benchConfig{
+		BaseURL:      server.URL,
+		Prompt:       "ping",
+		Mode:         benchModePoll,
+		Concurrency:  2,
+		Duration:     50 * time.Millisecond,
+		PollInterval: time.Millisecond,
+		MaxPolls:     2,
+	})
+
+	if report.Cycles == 0 || report.Succeeded == 0 {
+		t.Fatalf("expected completed cycles, got %+v", report)
+	}
+	if report.Errors["http_429"] != report.Failed {
+		t.Errorf("expected all failures to be 429s, got %v", report.Errors)
+	}
+	if report.RateLimited != report.Failed || report.RateLimitedRatio <= 0 {
+		t.Errorf("unexpected rate limit stats: %d (%f)", report.RateLimited, report.RateLimitedRatio)
+	}
+	if report.Latency["completion"].Count != report.Succeeded {
+		t.Errorf("expected %d completion samples, got %d", report.Succeeded, report.Latency["completion"].Count)
+	}
+
+	var buf bytes.Buffer
+	if err := writeBenchText(&buf, report); err != nil {
+		t.Fatalf("writeBenchText failed: %v", err)
+	}
+	if !strings.Contains(buf.String(), "http_429") {
+		t.Errorf("expected error breakdown in text output, got:\n%s", buf.String())
+	}
+	if _, err := json.Marshal(report); err != nil {
+		t.Errorf("report is not JSON encodable: %v", err)
+	}
+}
+
+func TestRunBench_Stream(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/run" {
+			_, _ = w.Write([]byte(`{"taskId": "t"}`))
+			return
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = w.Write([]byte("data: {\"taskId\": \"t\", \"status\": \"processing\"}\n\n"))
+		_, _ = w.Write([]byte("data: {\"taskId\": \"t\", \"status\": \"failed\", \"error\": \"boom\"}\n\n"))
+	}))
+	defer server.Close()
+
+	report := runBench(context.Background(), benchConfig{
+		BaseURL:     server.URL,
+		Prompt:      "ping",
+		Mode:        benchModeStream,
+		Concurrency: 1,
+		Duration:    20 * time.Millisecond,
+	})
+
+	if report.Failed == 0 || report.Errors["task_failed"] != report.Failed {
+		t.Errorf("expected task_failed errors, got %+v", report.Errors)
+	}
+	if report.Latency["first_event"].Count != report.Cycles {
+		t.Errorf("expected a first event sample per cycle, got %d/%d", report.Latency["first_event"].Count, report.Cycles)
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	samples := []time.Duration{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+	if got := percentile(samples, 50); got != 5 {
+		t.Errorf("p50: expected 5, got %d", got)
+	}
+	if got := percentile(samples, 99); got != 10 {
+		t.Errorf("p99: expected 10, got %d", got)
+	}
+}
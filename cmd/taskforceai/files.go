@@ -0,0 +1,117 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+
+	taskforceai "github.com/ClayWarren/taskforceai-sdk-go"
+)
+
+// stringList is a repeatable string flag.
+type stringList []string
+
+func (s *stringList) String() string     { return strings.Join(*s, ",") }
+func (s *stringList) Set(v string) error { *s = append(*s, v); return nil }
+
+func runFilesCommand(args []string, stdout, stderr io.Writer) error {
+	if len(args) == 0 {
+		fmt.Fprintln(stderr, "Usage: taskforceai files <subcommand> [flags]")
+		fmt.Fprintln(stderr)
+		fmt.Fprintln(stderr, "Subcommands:")
+		fmt.Fprintln(stderr, "  sync       Mirror a local directory into the files store")
+		return flag.ErrHelp
+	}
+
+	switch args[0] {
+	case "sync":
+		return runFilesSync(args[1:], stdout, stderr)
+	default:
+		return fmt.Errorf("unknown subcommand %q", args[0])
+	}
+}
+
+func runFilesSync(args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("files sync", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: taskforceai files sync [flags] <dir>")
+		fs.PrintDefaults()
+	}
+
+	var opts taskforceai.SyncOptions
+	var include, exclude stringList
+	baseURL := fs.String("base-url", taskforceai.DefaultBaseURL, "API base URL")
+	apiKey := fs.String("api-key", "", "API key (default $TASKFORCEAI_API_KEY)")
+	fs.StringVar(&opts.RemotePrefix, "prefix", "", "remote filename prefix for synced files")
+	fs.StringVar(&opts.Purpose, "purpose", "", "purpose of uploaded files; also limits which remote files are compared")
+	fs.Var(&include, "include", "only sync paths matching this pattern (repeatable)")
+	fs.Var(&exclude, "exclude", "skip paths matching this pattern (repeatable)")
+	fs.BoolVar(&opts.Delete, "delete", false, "delete remote files missing locally and stale duplicates")
+	fs.BoolVar(&opts.DryRun, "dry-run", false, "report changes without applying them")
+	jsonOut := fs.Bool("json", false, "print the change report as JSON")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return flag.ErrHelp
+	}
+	opts.Include, opts.Exclude = include, exclude
+	if *apiKey == "" {
+		*apiKey = apiKeyFromEnv()
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	client := taskforceai.NewClient(taskforceai.TaskForceAIOptions{APIKey: *apiKey, BaseURL: *baseURL})
+	report, syncErr := client.SyncDirectory(ctx, fs.Arg(0), &opts)
+	if report == nil {
+		return syncErr
+	}
+
+	if *jsonOut {
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(report); err != nil {
+			return err
+		}
+	} else {
+		writeSyncText(stdout, report)
+	}
+	return syncErr
+}
+
+func writeSyncText(w io.Writer, r *taskforceai.SyncReport) {
+	symbols := map[taskforceai.SyncAction]string{
+		taskforceai.SyncActionUpload: "+",
+		taskforceai.SyncActionUpdate: "~",
+		taskforceai.SyncActionDelete: "-",
+	}
+	for _, c := range r.Changes {
+		symbol, ok := symbols[c.Action]
+		if !ok {
+			continue
+		}
+		line := fmt.Sprintf("%s %s", symbol, c.Path)
+		if c.Error != "" {
+			line += "  (error: " + c.Error + ")"
+		}
+		fmt.Fprintln(w, line)
+	}
+
+	prefix := ""
+	if r.DryRun {
+		prefix = "dry run: "
+	}
+	fmt.Fprintf(w, "%s%d uploaded, %d updated, %d deleted, %d unchanged\n", prefix,
+		r.Count(taskforceai.SyncActionUpload), r.Count(taskforceai.SyncActionUpdate),
+		r.Count(taskforceai.SyncActionDelete), r.Count(taskforceai.SyncActionUnchanged))
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	taskforceai "github.com/ClayWarren/taskforceai-sdk-go"
+)
+
+func TestRunFilesSync(t *testing.T) {
+	var uploads int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer test-key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/files":
+			if r.URL.Query().Get("filename_prefix") != "kb/" {
+				t.Errorf("unexpected list query %v", r.URL.Query())
+			}
+			_, _ = w.Write([]byte(`{"files": [], "total": 0}`))
+		case r.Method == "POST" && r.URL.Path == "/files":
+			atomic.AddInt32(&uploads, 1)
+			_, _ = w.Write([]byte(`{"id": "file-1", "filename": "kb/notes.md"}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "notes.md"), []byte("hello"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	flags := []string{"files", "sync", "-base-url", server.URL, "-api-key", "test-key", "-prefix", "kb/"}
+
+	// 1. Dry run prints the plan without uploading.
+	var stdout, stderr bytes.Buffer
+	if code := run(append(append([]string{}, flags...), "-dry-run", dir), &stdout, &stderr); code != 0 {
+		t.Fatalf("dry run exited %d: %s", code, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "+ notes.md") || !strings.Contains(stdout.String(), "dry run: 1 uploaded") {
+		t.Errorf("unexpected dry run output:\n%s", stdout.String())
+	}
+	if uploads != 0 {
+		t.Errorf("dry run uploaded %d files", uploads)
+	}
+
+	// 2. A real run with -json reports the upload.
+	stdout.Reset()
+	if code := run(append(append([]string{}, flags...), "-json", dir), &stdout, &stderr); code != 0 {
+		t.Fatalf("sync exited %d: %s", code, stderr.String())
+	}
+	var report taskforceai.SyncReport
+	if err := json.Unmarshal(stdout.Bytes(), &report); err != nil {
+		t.Fatalf("invalid JSON report: %v\n%s", err, stdout.String())
+	}
+	if report.Count(taskforceai.SyncActionUpload) != 1 || report.Changes[0].FileID != "file-1" || uploads != 1 {
+		t.Errorf("unexpected report %+v after %d uploads", report, uploads)
+	}
+
+	// 3. A missing directory argument prints usage.
+	stderr.Reset()
+	if code := run([]string{"files", "sync"}, &stdout, &stderr); code != 2 || !strings.Contains(stderr.String(), "Usage: taskforceai files sync") {
+		t.Errorf("expected usage with exit 2, got %d:\n%s", code, stderr.String())
+	}
+}
@@ -0,0 +1,64 @@
+// Command taskforceai is a command-line companion to the TaskForceAI Go SDK.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+)
+
+type command struct {
+	name    string
+	summary string
+	run     func(args []string, stdout, stderr io.Writer) error
+}
+
+var commands = []command{
+	{name: "bench", summary: "Load-test the API with concurrent task cycles", run: runBenchCommand},
+	{name: "files", summary: "Manage uploaded files (sync)", run: runFilesCommand},
+	{name: "threads", summary: "Manage conversation threads (mirror)", run: runThreadsCommand},
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 || args[0] == "-h" || args[0] == "--help" || args[0] == "help" {
+		usage(stderr)
+		return 2
+	}
+
+	for _, cmd := range commands {
+		if cmd.name == args[0] {
+			if err := cmd.run(args[1:], stdout, stderr); err != nil {
+				if errors.Is(err, flag.ErrHelp) {
+					return 2
+				}
+				fmt.Fprintf(stderr, "taskforceai %s: %v\n", cmd.name, err)
+				return 1
+			}
+			return 0
+		}
+	}
+
+	fmt.Fprintf(stderr, "taskforceai: unknown command %q\n\n", args[0])
+	usage(stderr)
+	return 2
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: taskforceai <command> [flags]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %-10s %s\n", cmd.name, cmd.summary)
+	}
+}
+
+// apiKeyFromEnv returns the API key configured in the environment, if any.
+func apiKeyFromEnv() string {
+	return os.Getenv("TASKFORCEAI_API_KEY")
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"time"
+
+	taskforceai "github.com/ClayWarren/taskforceai-sdk-go"
+)
+
+func runThreadsCommand(args []string, stdout, stderr io.Writer) error {
+	if len(args) == 0 {
+		fmt.Fprintln(stderr, "Usage: taskforceai threads <subcommand> [flags]")
+		fmt.Fprintln(stderr)
+		fmt.Fprintln(stderr, "Subcommands:")
+		fmt.Fprintln(stderr, "  mirror     Mirror all threads into a folder of Markdown files")
+		return flag.ErrHelp
+	}
+
+	switch args[0] {
+	case "mirror":
+		return runThreadsMirror(args[1:], stdout, stderr)
+	default:
+		return fmt.Errorf("unknown subcommand %q", args[0])
+	}
+}
+
+func runThreadsMirror(args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("threads mirror", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: taskforceai threads mirror [flags] <dir>")
+		fs.PrintDefaults()
+	}
+
+	var opts taskforceai.MirrorOptions
+	baseURL := fs.String("base-url", taskforceai.DefaultBaseURL, "API base URL")
+	apiKey := fs.String("api-key", "", "API key (default $TASKFORCEAI_API_KEY)")
+	fs.StringVar(&opts.ArchiveDir, "archive-dir", "", "directory for files of deleted threads, relative to <dir> unless absolute (default \"archive\")")
+	interval := fs.Duration("interval", 0, "keep mirroring at this interval until interrupted (default: run once)")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return flag.ErrHelp
+	}
+	if *apiKey == "" {
+		*apiKey = apiKeyFromEnv()
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	client := taskforceai.NewClient(taskforceai.TaskForceAIOptions{APIKey: *apiKey, BaseURL: *baseURL})
+
+	if *interval <= 0 {
+		report, err := client.MirrorThreads(ctx, fs.Arg(0), &opts)
+		if report != nil {
+			writeMirrorText(stdout, report)
+		}
+		return err
+	}
+
+	err := client.RunThreadMirror(ctx, fs.Arg(0), *interval, &opts, func(report *taskforceai.MirrorReport, err error) {
+		if report != nil {
+			fmt.Fprint(stdout, time.Now().Format(time.RFC3339), " ")
+			writeMirrorText(stdout, report)
+		}
+		if err != nil {
+			fmt.Fprintf(stderr, "taskforceai threads mirror: %v\n", err)
+		}
+	})
+	if errors.Is(err, context.Canceled) {
+		return nil
+	}
+	return err
+}
+
+func writeMirrorText(w io.Writer, r *taskforceai.MirrorReport) {
+	fmt.Fprintf(w, "%d written, %d unchanged, %d archived\n", len(r.Written), r.Unchanged, len(r.Archived))
+}
@@ -0,0 +1,234 @@
+package taskforceai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Context policy defaults.
+const (
+	DefaultContextTokens    = 32000
+	DefaultContextThreshold = 0.8
+	DefaultContextKeepTurns = 4
+)
+
+// ThreadMetadataContextElisions is the thread metadata key under which a
+// ContextPolicy records each compaction as a ContextElision.
+const ThreadMetadataContextElisions = "context_elisions"
+
+// MaxContextElisions is the number of most recent compactions kept in a
+// thread's metadata.
+const MaxContextElisions = 20
+
+// ContextStrategy selects how a ContextPolicy shortens a thread.
+type ContextStrategy string
+
+const (
+	// ContextStrategySummarize replaces older messages with a system message
+	// summarizing them, produced by a task.
+	ContextStrategySummarize ContextStrategy = "summarize"
+	// ContextStrategyTruncate deletes older messages.
+	ContextStrategyTruncate ContextStrategy = "truncate"
+)
+
+// ContextPolicy keeps a thread's history within the model's context window.
+// Before a run, the tokens of the history and the new prompt are estimated;
+// above Threshold of the model's limit, messages older than the last
+// KeepTurns turns are summarized or deleted. Leading system messages are
+// always kept.
+type ContextPolicy struct {
+	Strategy ContextStrategy
+	// ModelLimits maps a model ID to its context size in tokens. The "" key
+	// applies to other models (default: DefaultContextTokens).
+	ModelLimits map[string]int
+	// Threshold is the fraction of the limit that triggers compaction
+	// (default: DefaultContextThreshold).
+	Threshold float64
+	// KeepTurns is the number of recent turns, each starting at a user
+	// message, kept verbatim (default: DefaultContextKeepTurns).
+	KeepTurns int
+	// EstimateTokens estimates the tokens in a text (default: EstimateTokens).
+	EstimateTokens func(string) int
+	// SummaryModelID is the model used to summarize (default: the run's).
+	SummaryModelID string
+}
+
+// ContextElision records one compaction of a thread's history.
+type ContextElision struct {
+	Strategy         ContextStrategy `json:"strategy"`
+	MessageIDs       []int           `json:"message_ids"`
+	EstimatedTokens  int             `json:"estimated_tokens"`
+	SummaryMessageID int             `json:"summary_message_id,omitempty"`
+	At               time.Time       `json:"at"`
+}
+
+// EstimateTokens approximates the number of tokens in text at four
+// characters per token.
+func EstimateTokens(text string) int {
+	return (utf8.RuneCountInString(text) + 3) / 4
+}
+
+// messageTokenOverhead approximates the per-message formatting tokens.
+const messageTokenOverhead = 4
+
+// applyContextPolicy compacts a thread before prompt is run in it with
+// modelID. It reports whether the history was changed.
+func (c *Client) applyContextPolicy(ctx context.Context, threadID int, modelID, prompt string, policy *ContextPolicy) (bool, error) {
+	if policy == nil {
+		return false, nil
+	}
+	p := *policy
+	if p.Strategy == "" {
+		p.Strategy = ContextStrategyTruncate
+	}
+	if p.Strategy != ContextStrategySummarize && p.Strategy != ContextStrategyTruncate {
+		return false, fmt.Errorf("unsupported context strategy %q", p.Strategy)
+	}
+	if p.Threshold <= 0 {
+		p.Threshold = DefaultContextThreshold
+	}
+	if p.KeepTurns <= 0 {
+		p.KeepTurns = DefaultContextKeepTurns
+	}
+	if p.EstimateTokens == nil {
+		p.EstimateTokens = EstimateTokens
+	}
+	limit, ok := p.ModelLimits[modelID]
+	if !ok {
+		limit, ok = p.ModelLimits[""]
+	}
+	if !ok || limit <= 0 {
+		limit = DefaultContextTokens
+	}
+
+	messages, err := c.listAllThreadMessages(ctx, threadID)
+	if err != nil {
+		return false, err
+	}
+
+	tokens := p.EstimateTokens(prompt) + messageTokenOverhead
+	for _, m := range messages {
+		tokens += p.EstimateTokens(m.Content) + messageTokenOverhead
+	}
+	if float64(tokens) <= p.Threshold*float64(limit) {
+		return false, nil
+	}
+
+	older := olderMessages(messages, p.KeepTurns)
+	if len(older) == 0 {
+		return false, nil
+	}
+
+	elision := ContextElision{Strategy: p.Strategy, EstimatedTokens: tokens, At: time.Now().UTC()}
+
+	remove := older
+	if p.Strategy == ContextStrategySummarize {
+		summaryModel := p.SummaryModelID
+		if summaryModel == "" {
+			summaryModel = modelID
+		}
+		summary, err := c.summarizeMessages(ctx, older, summaryModel)
+		if err != nil {
+			return false, err
+		}
+
+		// Replace the first elided message in place to keep the order.
+		content := "Summary of earlier conversation:\n\n" + summary
+		if _, err := c.UpdateThreadMessage(ctx, threadID, older[0].ID, UpdateThreadMessageOptions{
+			Role:        MessageRoleSystem,
+			Content:     &content,
+			Attachments: []Attachment{},
+		}); err != nil {
+			return false, err
+		}
+		elision.SummaryMessageID = older[0].ID
+		elision.MessageIDs = append(elision.MessageIDs, older[0].ID)
+		remove = older[1:]
+	}
+
+	// Only messages that were actually removed are recorded, so a failed
+	// delete still leaves an accurate record of the partial compaction.
+	var deleteErr error
+	for _, m := range remove {
+		if deleteErr = c.DeleteThreadMessage(ctx, threadID, m.ID); deleteErr != nil {
+			break
+		}
+		elision.MessageIDs = append(elision.MessageIDs, m.ID)
+	}
+	if len(elision.MessageIDs) == 0 {
+		return false, deleteErr
+	}
+
+	return true, errors.Join(deleteErr, c.recordContextElision(ctx, threadID, elision))
+}
+
+// olderMessages returns the messages before the last keepTurns turns,
+// excluding leading system messages.
+func olderMessages(messages []ThreadMessage, keepTurns int) []ThreadMessage {
+	start := 0
+	for start < len(messages) && messages[start].Role == MessageRoleSystem {
+		start++
+	}
+
+	cut := len(messages)
+	turns := 0
+	for i := len(messages) - 1; i >= start && turns < keepTurns; i-- {
+		if messages[i].Role == MessageRoleUser {
+			turns++
+			cut = i
+		}
+	}
+	if turns < keepTurns || cut <= start {
+		return nil
+	}
+	return messages[start:cut]
+}
+
+func (c *Client) summarizeMessages(ctx context.Context, messages []ThreadMessage, modelID string) (string, error) {
+	var b strings.Builder
+	b.WriteString("Summarize the following conversation so it can replace it as context for continuing the conversation. ")
+	b.WriteString("Keep facts, decisions, names and open questions; be concise.\n\n")
+	for _, m := range messages {
+		fmt.Fprintf(&b, "%s: %s\n\n", m.Role, m.Content)
+	}
+
+	status, err := c.RunTask(ctx, b.String(), &TaskSubmissionOptions{ModelID: modelID}, 0, 0, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to summarize thread history: %w", err)
+	}
+	if status.Result == nil || *status.Result == "" {
+		return "", fmt.Errorf("failed to summarize thread history: empty result")
+	}
+	return *status.Result, nil
+}
+
+// recordContextElision appends elision to the thread's metadata, keeping the
+// last MaxContextElisions entries. Records made through the same Client are
+// serialized so concurrent runs do not overwrite each other's entries.
+func (c *Client) recordContextElision(ctx context.Context, threadID int, elision ContextElision) error {
+	c.metadataMu.Lock()
+	defer c.metadataMu.Unlock()
+
+	thread, err := c.GetThread(ctx, threadID)
+	if err != nil {
+		return err
+	}
+
+	var elisions []any
+	if existing, ok := thread.Metadata[ThreadMetadataContextElisions].([]any); ok {
+		elisions = existing
+	}
+	elisions = append(elisions, elision)
+	if len(elisions) > MaxContextElisions {
+		elisions = elisions[len(elisions)-MaxContextElisions:]
+	}
+
+	_, err = c.UpdateThread(ctx, threadID, UpdateThreadOptions{
+		Metadata: map[string]any{ThreadMetadataContextElisions: elisions},
+	})
+	return err
+}
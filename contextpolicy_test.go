@@ -0,0 +1,188 @@
+package taskforceai
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestOlderMessages(t *testing.T) {
+	messages := []ThreadMessage{
+		{ID: 1, Role: MessageRoleSystem},
+		{ID: 2, Role: MessageRoleUser}, {ID: 3, Role: MessageRoleAssistant},
+		{ID: 4, Role: MessageRoleUser}, {ID: 5, Role: MessageRoleAssistant},
+		{ID: 6, Role: MessageRoleUser}, {ID: 7, Role: MessageRoleAssistant},
+	}
+
+	older := olderMessages(messages, 2)
+	if len(older) != 2 || older[0].ID != 2 || older[1].ID != 3 {
+		t.Errorf("unexpected older messages %+v", older)
+	}
+	if older := olderMessages(messages, 3); older != nil {
+		t.Errorf("expected nothing to elide, got %+v", older)
+	}
+}
+
+// fakeCompactionServer serves one thread whose messages can be edited, and
+// a summarization task.
+type fakeCompactionServer struct {
+	mu         sync.Mutex
+	messages   []ThreadMessage
+	metadata   map[string]any
+	failDelete int // message ID whose deletion fails
+}
+
+func (s *fakeCompactionServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch {
+	case r.URL.Path == "/threads/1/messages":
+		_ = json.NewEncoder(w).Encode(ThreadMessagesResponse{Messages: s.messages, Total: len(s.messages)})
+	case strings.HasPrefix(r.URL.Path, "/threads/1/messages/"):
+		id, _ := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/threads/1/messages/"))
+		for i, m := range s.messages {
+			if m.ID != id {
+				continue
+			}
+			if r.Method == "DELETE" && id == s.failDelete {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			if r.Method == "DELETE" {
+				s.messages = append(s.messages[:i], s.messages[i+1:]...)
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+			var body UpdateThreadMessageOptions
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			s.messages[i].Role, s.messages[i].Content = body.Role, *body.Content
+			_ = json.NewEncoder(w).Encode(s.messages[i])
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	case r.URL.Path == "/threads/1":
+		if r.Method == "PATCH" {
+			var body map[string]map[string]any
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			s.metadata = body["metadata"]
+		}
+		_ = json.NewEncoder(w).Encode(Thread{ID: 1, Metadata: s.metadata})
+	case r.URL.Path == "/run":
+		_ = json.NewEncoder(w).Encode(map[string]string{"taskId": "sum"})
+	case r.URL.Path == "/status/sum":
+		result := "they said hello"
+		_ = json.NewEncoder(w).Encode(TaskStatus{TaskID: "sum", Status: "completed", Result: &result})
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func TestClient_ApplyContextPolicy_Summarize(t *testing.T) {
+	long := strings.Repeat("word ", 100)
+	store := &fakeCompactionServer{messages: []ThreadMessage{
+		{ID: 1, Role: MessageRoleSystem, Content: "be brief"},
+		{ID: 2, Role: MessageRoleUser, Content: long},
+		{ID: 3, Role: MessageRoleAssistant, Content: long},
+		{ID: 4, Role: MessageRoleUser, Content: "latest"},
+		{ID: 5, Role: MessageRoleAssistant, Content: "ok"},
+	}}
+	server := httptest.NewServer(store)
+	defer server.Close()
+	client := NewClient(TaskForceAIOptions{BaseURL: server.URL})
+
+	policy := &ContextPolicy{Strategy: ContextStrategySummarize, ModelLimits: map[string]int{"": 200}, KeepTurns: 1}
+	compacted, err := client.applyContextPolicy(context.Background(), 1, "", "next", policy)
+	if err != nil || !compacted {
+		t.Fatalf("expected compaction, got %v, %v", compacted, err)
+	}
+
+	if len(store.messages) != 4 || store.messages[1].ID != 2 || store.messages[1].Role != MessageRoleSystem ||
+		!strings.Contains(store.messages[1].Content, "they said hello") {
+		t.Fatalf("unexpected messages after summarizing: %+v", store.messages)
+	}
+	elisions, _ := store.metadata[ThreadMetadataContextElisions].([]any)
+	if len(elisions) != 1 {
+		t.Fatalf("expected one recorded elision, got %v", store.metadata)
+	}
+	if e := elisions[0].(map[string]any); e["strategy"] != "summarize" || len(e["message_ids"].([]any)) != 2 {
+		t.Errorf("unexpected elision %v", e)
+	}
+
+	// The history is now below the limit.
+	compacted, err = client.applyContextPolicy(context.Background(), 1, "", "next", policy)
+	if err != nil || compacted {
+		t.Errorf("expected no compaction, got %v, %v", compacted, err)
+	}
+}
+
+func TestClient_ApplyContextPolicy_PartialDelete(t *testing.T) {
+	long := strings.Repeat("word ", 100)
+	store := &fakeCompactionServer{failDelete: 4, messages: []ThreadMessage{
+		{ID: 2, Role: MessageRoleUser, Content: long},
+		{ID: 3, Role: MessageRoleAssistant, Content: long},
+		{ID: 4, Role: MessageRoleUser, Content: long},
+		{ID: 5, Role: MessageRoleAssistant, Content: long},
+		{ID: 6, Role: MessageRoleUser, Content: "latest"},
+	}}
+	server := httptest.NewServer(store)
+	defer server.Close()
+	client := NewClient(TaskForceAIOptions{BaseURL: server.URL})
+
+	policy := &ContextPolicy{ModelLimits: map[string]int{"": 200}, KeepTurns: 1}
+	compacted, err := client.applyContextPolicy(context.Background(), 1, "", "next", policy)
+	if err == nil || !compacted {
+		t.Fatalf("expected partial compaction with an error, got %v, %v", compacted, err)
+	}
+
+	elisions, _ := store.metadata[ThreadMetadataContextElisions].([]any)
+	if len(elisions) != 1 {
+		t.Fatalf("expected the partial compaction to be recorded, got %v", store.metadata)
+	}
+	ids, _ := elisions[0].(map[string]any)["message_ids"].([]any)
+	if len(ids) != 2 || ids[0] != float64(2) || ids[1] != float64(3) {
+		t.Errorf("expected only removed messages to be recorded, got %v", ids)
+	}
+}
+
+func TestClient_RecordContextElision(t *testing.T) {
+	store := &fakeCompactionServer{}
+	server := httptest.NewServer(store)
+	defer server.Close()
+	client := NewClient(TaskForceAIOptions{BaseURL: server.URL})
+
+	// Concurrent records must not overwrite each other.
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if err := client.recordContextElision(context.Background(), 1, ContextElision{EstimatedTokens: i}); err != nil {
+				t.Error(err)
+			}
+		}(i)
+	}
+	wg.Wait()
+	if elisions, _ := store.metadata[ThreadMetadataContextElisions].([]any); len(elisions) != 10 {
+		t.Fatalf("expected 10 elisions, got %d", len(elisions))
+	}
+
+	// Only the most recent entries are kept.
+	for i := 0; i < MaxContextElisions; i++ {
+		if err := client.recordContextElision(context.Background(), 1, ContextElision{EstimatedTokens: 100 + i}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	elisions, _ := store.metadata[ThreadMetadataContextElisions].([]any)
+	if len(elisions) != MaxContextElisions {
+		t.Fatalf("expected %d elisions, got %d", MaxContextElisions, len(elisions))
+	}
+	if first := elisions[0].(map[string]any); first["estimated_tokens"] != float64(100) {
+		t.Errorf("expected oldest entries to be dropped, got %v", first)
+	}
+}
@@ -0,0 +1,176 @@
+package taskforceai
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ConversationOptions contains defaults for a Conversation.
+type ConversationOptions struct {
+	// Title and Metadata are used when the thread is created on the first
+	// Send. They are ignored by ResumeConversation.
+	Title    string
+	Metadata map[string]any
+	// ModelID and Options are sent with every turn.
+	ModelID string
+	Options map[string]interface{}
+	// PollInterval and MaxAttempts control waiting for each reply (defaults:
+	// DefaultPollInterval and DefaultMaxPoll).
+	PollInterval time.Duration
+	MaxAttempts  int
+	// ContextPolicy, if set, compacts the thread's history before each turn.
+	ContextPolicy *ContextPolicy
+}
+
+// Conversation is a chat session backed by a thread. It creates the thread
+// on the first Send and keeps a local copy of the message history. It is
+// safe for concurrent use; turns are serialized.
+type Conversation struct {
+	client *Client
+	opts   ConversationOptions
+
+	turn sync.Mutex // held for the duration of a Send
+
+	mu       sync.RWMutex
+	threadID int
+	history  []ThreadMessage
+}
+
+// NewConversation starts a conversation. No thread is created until the
+// first Send.
+func (c *Client) NewConversation(opts *ConversationOptions) *Conversation {
+	o := ConversationOptions{}
+	if opts != nil {
+		o = *opts
+	}
+	return &Conversation{client: c, opts: o}
+}
+
+// ResumeConversation continues the conversation in an existing thread and
+// loads its history.
+func (c *Client) ResumeConversation(ctx context.Context, threadID int, opts *ConversationOptions) (*Conversation, error) {
+	conv := c.NewConversation(opts)
+	conv.threadID = threadID
+	if err := conv.Refresh(ctx); err != nil {
+		return nil, err
+	}
+	return conv, nil
+}
+
+// ThreadID returns the ID of the conversation's thread, or 0 if it has not
+// been created yet.
+func (cv *Conversation) ThreadID() int {
+	cv.mu.RLock()
+	defer cv.mu.RUnlock()
+	return cv.threadID
+}
+
+// History returns a copy of the cached message history.
+func (cv *Conversation) History() []ThreadMessage {
+	cv.mu.RLock()
+	defer cv.mu.RUnlock()
+	return append([]ThreadMessage(nil), cv.history...)
+}
+
+// Refresh replaces the cached history with the thread's messages on the
+// server, for example after a failed Send or edits made elsewhere.
+func (cv *Conversation) Refresh(ctx context.Context) error {
+	cv.turn.Lock()
+	defer cv.turn.Unlock()
+
+	threadID := cv.ThreadID()
+	if threadID == 0 {
+		return nil
+	}
+
+	messages, err := cv.client.listAllThreadMessages(ctx, threadID)
+	if err != nil {
+		return err
+	}
+
+	cv.mu.Lock()
+	cv.history = messages
+	cv.mu.Unlock()
+	return nil
+}
+
+// Send runs prompt as the next turn and returns the assistant's reply. On
+// success the messages the server stored for the turn are appended to the
+// cached history; if the context policy compacted the thread, or the cache
+// no longer lines up with the server, the history is reloaded instead.
+func (cv *Conversation) Send(ctx context.Context, prompt string) (*ThreadMessage, error) {
+	if prompt == "" {
+		return nil, fmt.Errorf("prompt is required")
+	}
+
+	cv.turn.Lock()
+	defer cv.turn.Unlock()
+
+	threadID, err := cv.ensureThread(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	compacted, err := cv.client.applyContextPolicy(ctx, threadID, cv.opts.ModelID, prompt, cv.opts.ContextPolicy)
+	if err != nil {
+		return nil, err
+	}
+
+	reply, err := cv.client.RunInThreadAndWait(ctx, threadID, ThreadRunOptions{
+		Prompt:  prompt,
+		ModelID: cv.opts.ModelID,
+		Options: cv.opts.Options,
+	}, cv.opts.PollInterval, cv.opts.MaxAttempts, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	cv.mu.RLock()
+	cached := len(cv.history)
+	cv.mu.RUnlock()
+
+	if !compacted {
+		added, err := cv.client.listThreadMessagesFrom(ctx, threadID, cached)
+		if err != nil {
+			return reply, err
+		}
+		if len(added) > 0 && added[len(added)-1].ID == reply.ID {
+			cv.mu.Lock()
+			cv.history = append(cv.history, added...)
+			cv.mu.Unlock()
+			return reply, nil
+		}
+	}
+
+	// The cached history no longer matches the server's.
+	messages, err := cv.client.listAllThreadMessages(ctx, threadID)
+	if err != nil {
+		return reply, err
+	}
+	cv.mu.Lock()
+	cv.history = messages
+	cv.mu.Unlock()
+	return reply, nil
+}
+
+// ensureThread creates the thread on first use. The caller holds cv.turn.
+func (cv *Conversation) ensureThread(ctx context.Context) (int, error) {
+	if threadID := cv.ThreadID(); threadID != 0 {
+		return threadID, nil
+	}
+
+	thread, err := cv.client.CreateThread(ctx, &CreateThreadOptions{
+		Title:    cv.opts.Title,
+		Metadata: cv.opts.Metadata,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	cv.mu.Lock()
+	cv.threadID = thread.ID
+	cv.mu.Unlock()
+	return thread.ID, nil
+}
@@ -0,0 +1,114 @@
+package taskforceai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeThreadStore implements thread creation, runs that complete
+// immediately, and message retrieval.
+type fakeThreadStore struct {
+	mu       sync.Mutex
+	created  int
+	messages []ThreadMessage
+	active   int // runs in progress, to detect overlapping turns
+	overlap  bool
+}
+
+func (s *fakeThreadStore) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch {
+	case r.Method == "POST" && r.URL.Path == "/threads":
+		s.created++
+		_ = json.NewEncoder(w).Encode(Thread{ID: 1})
+	case r.Method == "POST" && r.URL.Path == "/threads/1/runs":
+		var body map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		if body["model_id"] != "model-x" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if s.active > 0 {
+			s.overlap = true
+		}
+		s.active++
+		prompt, _ := body["prompt"].(string)
+		s.messages = append(s.messages,
+			ThreadMessage{ID: len(s.messages) + 1, ThreadID: 1, Role: MessageRoleUser, Content: prompt},
+			ThreadMessage{ID: len(s.messages) + 2, ThreadID: 1, Role: MessageRoleAssistant, Content: "re: " + prompt},
+		)
+		id := len(s.messages)
+		_ = json.NewEncoder(w).Encode(ThreadRunResponse{TaskID: strconv.Itoa(id), ThreadID: 1, MessageID: id})
+	case strings.HasPrefix(r.URL.Path, "/status/"):
+		_ = json.NewEncoder(w).Encode(TaskStatus{TaskID: strings.TrimPrefix(r.URL.Path, "/status/"), Status: "completed"})
+	case r.URL.Path == "/threads/1/messages":
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		page := s.messages[min(offset, len(s.messages)):]
+		_ = json.NewEncoder(w).Encode(ThreadMessagesResponse{Messages: page, Total: len(s.messages)})
+	case strings.HasPrefix(r.URL.Path, "/threads/1/messages/"):
+		id, _ := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/threads/1/messages/"))
+		s.active--
+		_ = json.NewEncoder(w).Encode(s.messages[id-1])
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func TestConversation_Send(t *testing.T) {
+	store := &fakeThreadStore{}
+	server := httptest.NewServer(store)
+	defer server.Close()
+	client := NewClient(TaskForceAIOptions{BaseURL: server.URL})
+
+	conv := client.NewConversation(&ConversationOptions{ModelID: "model-x", PollInterval: 1})
+	if conv.ThreadID() != 0 {
+		t.Fatal("thread created before first send")
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			reply, err := conv.Send(context.Background(), fmt.Sprintf("q%d", i))
+			if err != nil || reply.Role != MessageRoleAssistant {
+				t.Errorf("Send failed: %+v %v", reply, err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if store.created != 1 || store.overlap {
+		t.Errorf("expected one thread and serialized turns, got %d threads, overlap %v", store.created, store.overlap)
+	}
+	history := conv.History()
+	if len(history) != 10 || conv.ThreadID() != 1 {
+		t.Fatalf("unexpected history %+v", history)
+	}
+	for i := 0; i < len(history); i += 2 {
+		if history[i+1].Content != "re: "+history[i].Content {
+			t.Errorf("turn %d out of order: %+v", i/2, history[i:i+2])
+		}
+		// The cached prompt is the server's stored message.
+		if history[i].ID != i+1 || history[i].Role != MessageRoleUser {
+			t.Errorf("unexpected cached user message %+v", history[i])
+		}
+	}
+
+	resumed, err := client.ResumeConversation(context.Background(), 1, &ConversationOptions{ModelID: "model-x"})
+	if err != nil {
+		t.Fatalf("ResumeConversation failed: %v", err)
+	}
+	if len(resumed.History()) != 10 {
+		t.Errorf("expected 10 resumed messages, got %d", len(resumed.History()))
+	}
+}
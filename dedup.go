@@ -0,0 +1,189 @@
+package taskforceai
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+)
+
+// FileIndex maps content digests to uploaded file IDs on the client side.
+type FileIndex interface {
+	Lookup(sha256 string) (fileID string, ok bool)
+	Store(sha256, fileID string) error
+	Forget(sha256 string) error
+}
+
+// DedupUploadOptions contains options for UploadFileDedup.
+type DedupUploadOptions struct {
+	FileUploadOptions
+
+	// Index, if set, is consulted before the server and updated after every
+	// upload or server-side match.
+	Index FileIndex
+	// SkipServerLookup disables the server-side lookup by digest.
+	SkipServerLookup bool
+}
+
+// UploadFileDedup uploads content unless a file with the same SHA-256 digest
+// already exists, in which case the existing file is returned and reused is
+// true. The digest is stored in the uploaded file's metadata under
+// FileMetadataSHA256. content is read twice: once to hash it and once to
+// upload it.
+func (c *Client) UploadFileDedup(ctx context.Context, filename string, content io.ReadSeeker, opts *DedupUploadOptions) (file *File, reused bool, err error) {
+	o := DedupUploadOptions{}
+	if opts != nil {
+		o = *opts
+	}
+
+	start, err := content.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return nil, false, err
+	}
+	hash := sha256.New()
+	size, err := io.Copy(hash, content)
+	if err != nil {
+		return nil, false, err
+	}
+	digest := hex.EncodeToString(hash.Sum(nil))
+
+	if o.Index != nil {
+		if id, ok := o.Index.Lookup(digest); ok {
+			// A stale entry may point at a deleted or replaced file, so only
+			// a file recording the same digest is reused.
+			existing, err := c.GetFile(ctx, id)
+			if err != nil && !isStatus(err, http.StatusNotFound) {
+				return nil, false, err
+			}
+			if err == nil && existing.Bytes == size && existing.SHA256() == digest {
+				return existing, true, nil
+			}
+			if err := o.Index.Forget(digest); err != nil {
+				return nil, false, err
+			}
+		}
+	}
+
+	if !o.SkipServerLookup {
+		existing, err := c.FindFileBySHA256(ctx, digest)
+		if err != nil {
+			return nil, false, err
+		}
+		if existing != nil && existing.Bytes == size {
+			if o.Index != nil {
+				if err := o.Index.Store(digest, existing.ID); err != nil {
+					return nil, false, err
+				}
+			}
+			return existing, true, nil
+		}
+	}
+
+	if _, err := content.Seek(start, io.SeekStart); err != nil {
+		return nil, false, err
+	}
+
+	uploadOpts := o.FileUploadOptions
+	uploadOpts.Metadata = make(map[string]string, len(o.Metadata)+1)
+	for k, v := range o.Metadata {
+		uploadOpts.Metadata[k] = v
+	}
+	uploadOpts.Metadata[FileMetadataSHA256] = digest
+
+	file, err = c.UploadFile(ctx, filename, content, &uploadOpts)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if o.Index != nil {
+		if err := o.Index.Store(digest, file.ID); err != nil {
+			return file, false, err
+		}
+	}
+	return file, false, nil
+}
+
+// FindFileBySHA256 looks up an uploaded file by the SHA-256 digest of its
+// content. It returns nil if no file matches.
+func (c *Client) FindFileBySHA256(ctx context.Context, digest string) (*File, error) {
+	path := "/files/lookup?" + url.Values{"sha256": {digest}}.Encode()
+
+	file, err := do[File](ctx, c, "GET", path, nil, "look up file")
+	if isStatus(err, http.StatusNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &file, nil
+}
+
+// JSONFileIndex is a FileIndex persisted as a JSON object in a local file.
+// It is safe for concurrent use within one process.
+type JSONFileIndex struct {
+	path string
+
+	mu      sync.Mutex
+	entries map[string]string
+}
+
+// OpenJSONFileIndex loads the index at path, starting empty if the file does
+// not exist yet.
+func OpenJSONFileIndex(path string) (*JSONFileIndex, error) {
+	idx := &JSONFileIndex{path: path, entries: map[string]string{}}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return idx, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &idx.entries); err != nil {
+		return nil, fmt.Errorf("failed to read file index %s: %w", path, err)
+	}
+	return idx, nil
+}
+
+// Lookup implements FileIndex.
+func (x *JSONFileIndex) Lookup(sha256 string) (string, bool) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	id, ok := x.entries[sha256]
+	return id, ok
+}
+
+// Store implements FileIndex.
+func (x *JSONFileIndex) Store(sha256, fileID string) error {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	x.entries[sha256] = fileID
+	return x.saveLocked()
+}
+
+// Forget implements FileIndex.
+func (x *JSONFileIndex) Forget(sha256 string) error {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	if _, ok := x.entries[sha256]; !ok {
+		return nil
+	}
+	delete(x.entries, sha256)
+	return x.saveLocked()
+}
+
+func (x *JSONFileIndex) saveLocked() error {
+	data, err := json.MarshalIndent(x.entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(x.path, data)
+}
@@ -0,0 +1,116 @@
+package taskforceai
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestClient_UploadFileDedup(t *testing.T) {
+	store := &fakeFileStore{}
+	server := httptest.NewServer(store)
+	defer server.Close()
+	client := NewClient(TaskForceAIOptions{BaseURL: server.URL})
+
+	index, err := OpenJSONFileIndex(filepath.Join(t.TempDir(), "index.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sum := sha256.Sum256([]byte("report"))
+	digest := hex.EncodeToString(sum[:])
+	opts := &DedupUploadOptions{Index: index}
+
+	// 1. Miss: uploaded with the digest recorded in metadata and the index.
+	file, reused, err := client.UploadFileDedup(context.Background(), "report.txt", strings.NewReader("report"), opts)
+	if err != nil || reused {
+		t.Fatalf("first upload = %v, reused %v", err, reused)
+	}
+	if file.SHA256() != digest {
+		t.Errorf("expected digest in metadata, got %v", file.Metadata)
+	}
+	if id, ok := index.Lookup(digest); !ok || id != file.ID {
+		t.Errorf("expected index entry for %s, got %q", file.ID, id)
+	}
+
+	// 2. Index hit: the existing file is reused.
+	again, reused, err := client.UploadFileDedup(context.Background(), "report.txt", strings.NewReader("report"), opts)
+	if err != nil || !reused || again.ID != file.ID || len(store.files) != 1 {
+		t.Fatalf("expected reuse of %s, got %+v reused %v err %v", file.ID, again, reused, err)
+	}
+
+	// 3. Stale entry: a file of the same size without the digest is not
+	// trusted, and the server lookup misses, so the content is uploaded.
+	store.mu.Lock()
+	store.files = []File{{ID: "file-plain", Bytes: 6}}
+	store.mu.Unlock()
+	if err := index.Store(digest, "file-plain"); err != nil {
+		t.Fatal(err)
+	}
+	fresh, reused, err := client.UploadFileDedup(context.Background(), "report.txt", strings.NewReader("report"), opts)
+	if err != nil || reused || fresh.ID == "file-plain" {
+		t.Fatalf("expected fresh upload, got %+v reused %v err %v", fresh, reused, err)
+	}
+	if id, _ := index.Lookup(digest); id != fresh.ID {
+		t.Errorf("expected index to point at %s, got %q", fresh.ID, id)
+	}
+
+	// 4. Entry for a deleted file: dropped, then matched on the server.
+	if err := index.Store(digest, "file-gone"); err != nil {
+		t.Fatal(err)
+	}
+	found, reused, err := client.UploadFileDedup(context.Background(), "report.txt", strings.NewReader("report"), opts)
+	if err != nil || !reused || found.ID != fresh.ID {
+		t.Fatalf("expected server match %s, got %+v reused %v err %v", fresh.ID, found, reused, err)
+	}
+	if id, _ := index.Lookup(digest); id != fresh.ID {
+		t.Errorf("expected index to be repaired, got %q", id)
+	}
+}
+
+func TestClient_FindFileBySHA256(t *testing.T) {
+	store := &fakeFileStore{files: []File{{ID: "file-1", Metadata: map[string]string{FileMetadataSHA256: "abc"}}}}
+	server := httptest.NewServer(store)
+	defer server.Close()
+	client := NewClient(TaskForceAIOptions{BaseURL: server.URL})
+
+	file, err := client.FindFileBySHA256(context.Background(), "abc")
+	if err != nil || file == nil || file.ID != "file-1" {
+		t.Errorf("expected file-1, got %+v, %v", file, err)
+	}
+	file, err = client.FindFileBySHA256(context.Background(), "def")
+	if err != nil || file != nil {
+		t.Errorf("expected no match, got %+v, %v", file, err)
+	}
+}
+
+func TestJSONFileIndex(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "index.json")
+	index, err := OpenJSONFileIndex(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := index.Store("abc", "file-1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := index.Store("def", "file-2"); err != nil {
+		t.Fatal(err)
+	}
+	if err := index.Forget("def"); err != nil {
+		t.Fatal(err)
+	}
+
+	reopened, err := OpenJSONFileIndex(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id, ok := reopened.Lookup("abc"); !ok || id != "file-1" {
+		t.Errorf("expected persisted entry, got %q %v", id, ok)
+	}
+	if _, ok := reopened.Lookup("def"); ok {
+		t.Error("expected forgotten entry to stay gone")
+	}
+}
@@ -0,0 +1,397 @@
+package taskforceai
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultDownloadPartSize = 8 << 20
+	DefaultDownloadRetries  = 3
+)
+
+// DownloadToOptions contains options for DownloadFileTo and DownloadFileAt.
+type DownloadToOptions struct {
+	DownloadOptions
+
+	// Parallelism is the number of ranges fetched concurrently. Values above
+	// one enable parallel downloads for files larger than PartSize.
+	Parallelism int
+	// PartSize is the size of each parallel range (default: 8 MiB).
+	PartSize int64
+	// MaxRetries is the number of times an interrupted transfer is resumed
+	// (default: 3).
+	MaxRetries int
+	// SkipVerify disables the size and checksum checks against File metadata.
+	SkipVerify bool
+}
+
+// DownloadFileTo downloads a file to path. Data is written to path+".part"
+// and renamed into place only after its size and SHA-256 digest (when the
+// file's metadata has one) match. A leftover ".part" file from an earlier
+// attempt is resumed with a Range request. Parallel downloads always start
+// from scratch.
+func (c *Client) DownloadFileTo(ctx context.Context, fileID, path string, opts *DownloadToOptions) (*File, error) {
+	o := normalizeDownloadToOptions(opts)
+
+	file, err := c.GetFile(ctx, fileID)
+	if err != nil {
+		return nil, err
+	}
+
+	partPath := path + ".part"
+	if o.Parallelism > 1 && file.Bytes > o.PartSize {
+		err = c.downloadParallelToPath(ctx, file, partPath, o)
+	} else {
+		err = c.downloadResumable(ctx, file, partPath, o)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if !o.SkipVerify {
+		if err := verifyDownload(file, partPath); err != nil {
+			_ = os.Remove(partPath)
+			return nil, err
+		}
+	}
+
+	if err := os.Rename(partPath, path); err != nil {
+		return nil, err
+	}
+	return file, nil
+}
+
+// DownloadFileAt downloads a file into w using parallel range requests and
+// returns its metadata. Content is not verified, since w cannot be read back.
+func (c *Client) DownloadFileAt(ctx context.Context, fileID string, w io.WriterAt, opts *DownloadToOptions) (*File, error) {
+	o := normalizeDownloadToOptions(opts)
+
+	file, err := c.GetFile(ctx, fileID)
+	if err != nil {
+		return nil, err
+	}
+
+	tracker := newProgressTracker(o.Progress, o.ProgressInterval, file.Bytes)
+	if err := c.downloadRanges(ctx, file, w, o, tracker); err != nil {
+		return nil, err
+	}
+	tracker.finish()
+	return file, nil
+}
+
+func normalizeDownloadToOptions(opts *DownloadToOptions) DownloadToOptions {
+	o := DownloadToOptions{}
+	if opts != nil {
+		o = *opts
+	}
+	if o.Parallelism <= 0 {
+		o.Parallelism = 1
+	}
+	if o.PartSize <= 0 {
+		o.PartSize = DefaultDownloadPartSize
+	}
+	if o.MaxRetries <= 0 {
+		o.MaxRetries = DefaultDownloadRetries
+	}
+	return o
+}
+
+// downloadResumable appends the missing tail of file to partPath, resuming
+// after interruptions.
+func (c *Client) downloadResumable(ctx context.Context, file *File, partPath string, o DownloadToOptions) error {
+	f, err := os.OpenFile(partPath, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+
+	offset, err := f.Seek(0, io.SeekEnd)
+	if err != nil {
+		return err
+	}
+	if offset > file.Bytes {
+		if err := f.Truncate(0); err != nil {
+			return err
+		}
+		offset = 0
+	}
+
+	tracker := newProgressTracker(o.Progress, o.ProgressInterval, file.Bytes)
+	tracker.resume(offset)
+
+	for attempt := 0; offset < file.Bytes || (file.Bytes == 0 && attempt == 0); attempt++ {
+		if attempt > 0 {
+			if attempt > o.MaxRetries {
+				return fmt.Errorf("failed to download file: giving up after %d retries", o.MaxRetries)
+			}
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(chunkBackoff(attempt)):
+			}
+		}
+
+		n, restarted, err := c.fetchRange(ctx, file.ID, offset, -1, f, tracker)
+		if restarted {
+			offset = 0
+		}
+		offset += n
+		if n > 0 {
+			// Retries are only counted while no progress is made.
+			attempt = 0
+		}
+		if err == nil {
+			// A body that ended early is resumed like an interrupted one.
+			continue
+		}
+		var retryable errRetryable
+		if !errors.As(err, &retryable) || ctx.Err() != nil {
+			return err
+		}
+	}
+
+	tracker.finish()
+	return nil
+}
+
+func (c *Client) downloadParallelToPath(ctx context.Context, file *File, partPath string, o DownloadToOptions) error {
+	f, err := os.OpenFile(partPath, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+
+	if err := f.Truncate(file.Bytes); err != nil {
+		return err
+	}
+
+	tracker := newProgressTracker(o.Progress, o.ProgressInterval, file.Bytes)
+	if err := c.downloadRanges(ctx, file, f, o, tracker); err != nil {
+		return err
+	}
+	tracker.finish()
+	return f.Sync()
+}
+
+// downloadRanges fetches file in PartSize ranges with o.Parallelism workers.
+func (c *Client) downloadRanges(ctx context.Context, file *File, w io.WriterAt, o DownloadToOptions, tracker *progressTracker) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	starts := make(chan int64)
+	var wg sync.WaitGroup
+	var once sync.Once
+	var firstErr error
+
+	for i := 0; i < o.Parallelism; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for start := range starts {
+				end := start + o.PartSize - 1
+				if end >= file.Bytes {
+					end = file.Bytes - 1
+				}
+				if err := c.fetchRangeWithRetry(ctx, file.ID, start, end, w, o.MaxRetries, tracker); err != nil {
+					once.Do(func() {
+						firstErr = err
+						cancel()
+					})
+				}
+			}
+		}()
+	}
+
+	for start := int64(0); start < file.Bytes && ctx.Err() == nil; start += o.PartSize {
+		select {
+		case starts <- start:
+		case <-ctx.Done():
+		}
+	}
+	close(starts)
+	wg.Wait()
+
+	if firstErr != nil {
+		return firstErr
+	}
+	return ctx.Err()
+}
+
+func (c *Client) fetchRangeWithRetry(ctx context.Context, fileID string, start, end int64, w io.WriterAt, retries int, tracker *progressTracker) error {
+	offset := start
+	var err error
+	for attempt := 0; attempt <= retries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(chunkBackoff(attempt)):
+			}
+		}
+
+		var n int64
+		var restarted bool
+		n, restarted, err = c.fetchRange(ctx, fileID, offset, end, io.NewOffsetWriter(w, offset), tracker)
+		if restarted {
+			return fmt.Errorf("failed to download file: server does not support range requests")
+		}
+		offset += n
+		if n > 0 {
+			attempt = 0
+		}
+		if err == nil {
+			if offset > end {
+				return nil
+			}
+			err = fmt.Errorf("failed to download file: range ended at byte %d, want %d", offset, end+1)
+			continue
+		}
+		var retryable errRetryable
+		if !errors.As(err, &retryable) || ctx.Err() != nil {
+			return err
+		}
+	}
+	return err
+}
+
+// fetchRange requests bytes [start, end] of a file (end < 0 means to the end)
+// and copies them to w. When start > 0 and the server ignores the Range
+// header, w must be an *os.File; it is truncated, the full body is written
+// from the beginning and restarted is true. The transfer is not bounded by
+// the client timeout; instead it is aborted when no data arrives for that
+// long.
+func (c *Client) fetchRange(ctx context.Context, fileID string, start, end int64, w io.Writer, tracker *progressTracker) (n int64, restarted bool, err error) {
+	reqCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	idle := time.AfterFunc(c.timeout, cancel)
+	defer idle.Stop()
+
+	req, err := c.newRequest(reqCtx, "GET", "/files/"+fileID+"/content", nil)
+	if err != nil {
+		return 0, false, err
+	}
+	if start > 0 || end >= 0 {
+		rng := "bytes=" + strconv.FormatInt(start, 10) + "-"
+		if end >= 0 {
+			rng += strconv.FormatInt(end, 10)
+		}
+		req.Header.Set("Range", rng)
+	}
+
+	resp, err := c.sendStreaming(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return 0, false, ctx.Err()
+		}
+		return 0, false, errRetryable{err}
+	}
+	defer closeResponse(resp)
+
+	switch {
+	case resp.StatusCode == http.StatusPartialContent:
+		if got, ok := contentRangeStart(resp.Header.Get("Content-Range")); !ok || got != start {
+			return 0, false, fmt.Errorf("failed to download file: server returned range %q, want start %d",
+				resp.Header.Get("Content-Range"), start)
+		}
+	case resp.StatusCode == http.StatusOK:
+		if start > 0 || end >= 0 {
+			f, ok := w.(*os.File)
+			if !ok {
+				return 0, true, nil
+			}
+			if err := f.Truncate(0); err != nil {
+				return 0, true, err
+			}
+			if _, err := f.Seek(0, io.SeekStart); err != nil {
+				return 0, true, err
+			}
+			tracker.add(-start)
+			restarted = true
+		}
+	default:
+		err := checkResponse(resp, "download file")
+		if err == nil {
+			err = fmt.Errorf("failed to download file: unexpected status %d", resp.StatusCode)
+		}
+		if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500 {
+			return 0, false, errRetryable{err}
+		}
+		return 0, false, err
+	}
+
+	body := &idleReader{r: resp.Body, timer: idle, timeout: c.timeout}
+	n, err = io.Copy(w, &progressReader{r: body, tracker: tracker})
+	if err != nil {
+		if ctx.Err() != nil {
+			return n, restarted, ctx.Err()
+		}
+		return n, restarted, errRetryable{err}
+	}
+	return n, restarted, nil
+}
+
+// contentRangeStart returns the first byte position of a Content-Range header
+// such as "bytes 100-199/1000".
+func contentRangeStart(header string) (int64, bool) {
+	spec, ok := strings.CutPrefix(header, "bytes ")
+	if !ok {
+		return 0, false
+	}
+	first, _, ok := strings.Cut(spec, "-")
+	if !ok {
+		return 0, false
+	}
+	start, err := strconv.ParseInt(first, 10, 64)
+	return start, err == nil
+}
+
+// idleReader pushes back timer by timeout whenever data arrives.
+type idleReader struct {
+	r       io.Reader
+	timer   *time.Timer
+	timeout time.Duration
+}
+
+func (r *idleReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	if n > 0 {
+		r.timer.Reset(r.timeout)
+	}
+	return n, err
+}
+
+// verifyDownload checks the size and, when known, the SHA-256 digest of the
+// downloaded file at path against file's metadata.
+func verifyDownload(file *File, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+
+	hash := sha256.New()
+	size, err := io.Copy(hash, f)
+	if err != nil {
+		return err
+	}
+	if size != file.Bytes {
+		return fmt.Errorf("downloaded file size mismatch: got %d bytes, want %d", size, file.Bytes)
+	}
+	if want := file.SHA256(); want != "" {
+		if got := hex.EncodeToString(hash.Sum(nil)); got != want {
+			return fmt.Errorf("downloaded file checksum mismatch: got %s, want %s", got, want)
+		}
+	}
+	return nil
+}
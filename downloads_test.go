@@ -0,0 +1,184 @@
+package taskforceai
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newContentServer(content []byte, digest string, ranges *int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/content") {
+			if r.Header.Get("Range") != "" {
+				atomic.AddInt32(ranges, 1)
+			}
+			http.ServeContent(w, r, "data.bin", time.Time{}, bytes.NewReader(content))
+			return
+		}
+		_, _ = w.Write([]byte(`{"id": "file-1", "filename": "data.bin", "bytes": ` +
+			strconv.Itoa(len(content)) + `, "metadata": {"sha256": "` + digest + `"}}`))
+	}))
+}
+
+func TestClient_DownloadFileTo_Resume(t *testing.T) {
+	content := []byte(strings.Repeat("taskforceai", 100))
+	sum := sha256.Sum256(content)
+	var ranges int32
+	server := newContentServer(content, hex.EncodeToString(sum[:]), &ranges)
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "data.bin")
+	if err := os.WriteFile(path+".part", content[:300], 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	client := NewClient(TaskForceAIOptions{BaseURL: server.URL})
+	if _, err := client.DownloadFileTo(context.Background(), "file-1", path, nil); err != nil {
+		t.Fatalf("DownloadFileTo failed: %v", err)
+	}
+
+	got, err := os.ReadFile(path)
+	if err != nil || !bytes.Equal(got, content) {
+		t.Errorf("unexpected content (err %v)", err)
+	}
+	if ranges != 1 {
+		t.Errorf("expected one range request, got %d", ranges)
+	}
+	if _, err := os.Stat(path + ".part"); !os.IsNotExist(err) {
+		t.Errorf("expected part file to be renamed, got %v", err)
+	}
+}
+
+func TestClient_DownloadFileTo_Parallel(t *testing.T) {
+	content := []byte(strings.Repeat("0123456789", 50))
+	sum := sha256.Sum256(content)
+	var ranges int32
+	server := newContentServer(content, hex.EncodeToString(sum[:]), &ranges)
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "data.bin")
+	client := NewClient(TaskForceAIOptions{BaseURL: server.URL})
+	_, err := client.DownloadFileTo(context.Background(), "file-1", path, &DownloadToOptions{Parallelism: 3, PartSize: 64})
+	if err != nil {
+		t.Fatalf("parallel DownloadFileTo failed: %v", err)
+	}
+
+	got, _ := os.ReadFile(path)
+	if !bytes.Equal(got, content) {
+		t.Error("parallel download produced different content")
+	}
+	if ranges != 8 {
+		t.Errorf("expected 8 range requests, got %d", ranges)
+	}
+}
+
+func TestClient_DownloadFileTo_ChecksumMismatch(t *testing.T) {
+	var ranges int32
+	server := newContentServer([]byte("tampered"), strings.Repeat("0", 64), &ranges)
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "data.bin")
+	client := NewClient(TaskForceAIOptions{BaseURL: server.URL})
+	_, err := client.DownloadFileTo(context.Background(), "file-1", path, nil)
+	if err == nil || !strings.Contains(err.Error(), "checksum mismatch") {
+		t.Fatalf("expected checksum mismatch, got %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("expected no file at destination, got %v", err)
+	}
+}
+
+func TestClient_DownloadFileTo_InterruptedRepeatedly(t *testing.T) {
+	content := []byte(strings.Repeat("abcd", 100))
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "/content") {
+			_, _ = w.Write([]byte(`{"id": "file-1", "bytes": ` + strconv.Itoa(len(content)) + `}`))
+			return
+		}
+		var start int
+		if rng := r.Header.Get("Range"); rng != "" {
+			start, _ = strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(rng, "bytes="), "-"))
+			w.Header().Set("Content-Range", "bytes "+strconv.Itoa(start)+"-"+strconv.Itoa(len(content)-1)+"/"+strconv.Itoa(len(content)))
+		}
+		// Promise the whole remainder but send at most 100 bytes.
+		w.Header().Set("Content-Length", strconv.Itoa(len(content)-start))
+		if start > 0 {
+			w.WriteHeader(http.StatusPartialContent)
+		}
+		_, _ = w.Write(content[start:min(start+100, len(content))])
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "data.bin")
+	client := NewClient(TaskForceAIOptions{BaseURL: server.URL})
+	if _, err := client.DownloadFileTo(context.Background(), "file-1", path, &DownloadToOptions{MaxRetries: 1}); err != nil {
+		t.Fatalf("DownloadFileTo failed: %v", err)
+	}
+	if got, _ := os.ReadFile(path); !bytes.Equal(got, content) {
+		t.Error("unexpected content")
+	}
+}
+
+func TestClient_DownloadFileTo_ContentRangeMismatch(t *testing.T) {
+	content := []byte(strings.Repeat("abcd", 100))
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "/content") {
+			_, _ = w.Write([]byte(`{"id": "file-1", "bytes": ` + strconv.Itoa(len(content)) + `}`))
+			return
+		}
+		w.Header().Set("Content-Range", "bytes 0-399/400")
+		w.WriteHeader(http.StatusPartialContent)
+		_, _ = w.Write(content)
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "data.bin")
+	if err := os.WriteFile(path+".part", content[:100], 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	client := NewClient(TaskForceAIOptions{BaseURL: server.URL})
+	_, err := client.DownloadFileTo(context.Background(), "file-1", path, nil)
+	if err == nil || !strings.Contains(err.Error(), "want start 100") {
+		t.Fatalf("expected range mismatch, got %v", err)
+	}
+	if got, _ := os.ReadFile(path + ".part"); len(got) != 100 {
+		t.Errorf("expected part file to be left alone, got %d bytes", len(got))
+	}
+}
+
+func TestClient_DownloadFileTo_SlowBody(t *testing.T) {
+	content := []byte(strings.Repeat("x", 500))
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "/content") {
+			_, _ = w.Write([]byte(`{"id": "file-1", "bytes": ` + strconv.Itoa(len(content)) + `}`))
+			return
+		}
+		for i := 0; i < len(content); i += 100 {
+			_, _ = w.Write(content[i : i+100])
+			w.(http.Flusher).Flush()
+			time.Sleep(40 * time.Millisecond)
+		}
+	}))
+	defer server.Close()
+
+	// The body takes longer than Timeout in total but never stalls for that long.
+	path := filepath.Join(t.TempDir(), "data.bin")
+	client := NewClient(TaskForceAIOptions{BaseURL: server.URL, Timeout: 100 * time.Millisecond})
+	if _, err := client.DownloadFileTo(context.Background(), "file-1", path, &DownloadToOptions{MaxRetries: 1}); err != nil {
+		t.Fatalf("DownloadFileTo failed: %v", err)
+	}
+	if got, _ := os.ReadFile(path); !bytes.Equal(got, content) {
+		t.Error("unexpected content")
+	}
+}
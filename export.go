@@ -0,0 +1,235 @@
+package taskforceai
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ExportFormat selects the output of ExportThread.
+type ExportFormat string
+
+const (
+	ExportFormatJSON     ExportFormat = "json"     // lossless ThreadExport, accepted by ImportThread
+	ExportFormatJSONL    ExportFormat = "jsonl"    // one ThreadMessage per line
+	ExportFormatMarkdown ExportFormat = "markdown" // human-readable transcript
+)
+
+// threadExportVersion is the current ThreadExport format version.
+const threadExportVersion = 1
+
+// ThreadExport is the lossless JSON representation of a thread.
+type ThreadExport struct {
+	Version    int             `json:"version"`
+	ExportedAt time.Time       `json:"exported_at"`
+	Thread     Thread          `json:"thread"`
+	Messages   []ThreadMessage `json:"messages"`
+}
+
+// ExportThread writes a thread and all of its messages to w in the given
+// format.
+func (c *Client) ExportThread(ctx context.Context, threadID int, format ExportFormat, w io.Writer) error {
+	if _, err := format.extension(); err != nil {
+		return err
+	}
+
+	export, err := c.exportThread(ctx, threadID)
+	if err != nil {
+		return err
+	}
+
+	return export.write(format, w)
+}
+
+// ExportAllThreads exports every thread into dir, one file per thread named
+// thread-<id> with the format's extension, and returns the paths written.
+func (c *Client) ExportAllThreads(ctx context.Context, dir string, format ExportFormat) ([]string, error) {
+	ext, err := format.extension()
+	if err != nil {
+		return nil, err
+	}
+
+	threads, err := c.listAllThreads(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	paths := make([]string, 0, len(threads))
+	for _, thread := range threads {
+		export, err := c.exportThread(ctx, thread.ID)
+		if err != nil {
+			return paths, fmt.Errorf("failed to export thread %d: %w", thread.ID, err)
+		}
+
+		var buf bytes.Buffer
+		if err := export.write(format, &buf); err != nil {
+			return paths, err
+		}
+
+		path := filepath.Join(dir, fmt.Sprintf("thread-%d%s", thread.ID, ext))
+		if err := writeFileAtomic(path, buf.Bytes()); err != nil {
+			return paths, err
+		}
+		paths = append(paths, path)
+	}
+
+	return paths, nil
+}
+
+// ImportThread creates a new thread with the title, metadata and messages
+// of a JSON export. Message IDs are assigned by the server; roles, content,
+// attachments and timestamps are preserved.
+func (c *Client) ImportThread(ctx context.Context, r io.Reader) (*Thread, error) {
+	var export ThreadExport
+	if err := json.NewDecoder(r).Decode(&export); err != nil {
+		return nil, fmt.Errorf("invalid thread export: %w", err)
+	}
+	if export.Version > threadExportVersion {
+		return nil, fmt.Errorf("unsupported thread export version %d", export.Version)
+	}
+
+	messages := make([]ThreadMessage, len(export.Messages))
+	for i, m := range export.Messages {
+		if err := validateMessageRole(m.Role); err != nil {
+			return nil, fmt.Errorf("message %d: %w", i, err)
+		}
+		m.ID, m.ThreadID = 0, 0
+		messages[i] = m
+	}
+
+	return c.CreateThread(ctx, &CreateThreadOptions{
+		Title:    export.Thread.Title,
+		Messages: messages,
+		Metadata: export.Thread.Metadata,
+	})
+}
+
+func (c *Client) exportThread(ctx context.Context, threadID int) (*ThreadExport, error) {
+	thread, err := c.GetThread(ctx, threadID)
+	if err != nil {
+		return nil, err
+	}
+
+	messages, err := c.listAllThreadMessages(ctx, threadID)
+	if err != nil {
+		return nil, err
+	}
+	if messages == nil {
+		messages = []ThreadMessage{}
+	}
+
+	return &ThreadExport{
+		Version:    threadExportVersion,
+		ExportedAt: time.Now().UTC(),
+		Thread:     *thread,
+		Messages:   messages,
+	}, nil
+}
+
+func (f ExportFormat) extension() (string, error) {
+	switch f {
+	case ExportFormatJSON:
+		return ".json", nil
+	case ExportFormatJSONL:
+		return ".jsonl", nil
+	case ExportFormatMarkdown:
+		return ".md", nil
+	default:
+		return "", fmt.Errorf("unsupported export format %q", f)
+	}
+}
+
+func (e *ThreadExport) write(format ExportFormat, w io.Writer) error {
+	switch format {
+	case ExportFormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(e)
+	case ExportFormatJSONL:
+		enc := json.NewEncoder(w)
+		for _, m := range e.Messages {
+			if err := enc.Encode(m); err != nil {
+				return err
+			}
+		}
+		return nil
+	case ExportFormatMarkdown:
+		return e.writeMarkdown(w)
+	default:
+		return fmt.Errorf("unsupported export format %q", format)
+	}
+}
+
+func (e *ThreadExport) writeMarkdown(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+
+	fmt.Fprintf(bw, "# %s\n\n", e.title())
+	fmt.Fprintf(bw, "- Thread: %d\n", e.Thread.ID)
+	if !e.Thread.CreatedAt.IsZero() {
+		fmt.Fprintf(bw, "- Created: %s\n", e.Thread.CreatedAt.Format(time.RFC3339))
+	}
+	if !e.Thread.UpdatedAt.IsZero() {
+		fmt.Fprintf(bw, "- Updated: %s\n", e.Thread.UpdatedAt.Format(time.RFC3339))
+	}
+	for _, k := range sortedKeys(e.Thread.Metadata) {
+		fmt.Fprintf(bw, "- %s: %v\n", k, e.Thread.Metadata[k])
+	}
+	e.writeMarkdownMessages(bw)
+
+	return bw.Flush()
+}
+
+func (e *ThreadExport) title() string {
+	if e.Thread.Title == "" {
+		return fmt.Sprintf("Thread %d", e.Thread.ID)
+	}
+	return e.Thread.Title
+}
+
+func (e *ThreadExport) writeMarkdownMessages(w io.Writer) {
+	for _, m := range e.Messages {
+		heading := roleTitle(m.Role)
+		if !m.CreatedAt.IsZero() {
+			heading += " (" + m.CreatedAt.Format(time.RFC3339) + ")"
+		}
+		fmt.Fprintf(w, "\n## %s\n\n%s\n", heading, strings.TrimRight(m.Content, "\n"))
+		for _, a := range m.Attachments {
+			if a.Role != "" {
+				fmt.Fprintf(w, "\n- Attachment: `%s` (%s)", a.FileID, a.Role)
+			} else {
+				fmt.Fprintf(w, "\n- Attachment: `%s`", a.FileID)
+			}
+		}
+		if len(m.Attachments) > 0 {
+			fmt.Fprintln(w)
+		}
+	}
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func roleTitle(role string) string {
+	if role == "" {
+		return "Unknown"
+	}
+	return strings.ToUpper(role[:1]) + role[1:]
+}
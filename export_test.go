@@ -0,0 +1,76 @@
+package taskforceai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestClient_ExportImportThread(t *testing.T) {
+	created := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	messages := []ThreadMessage{
+		{ID: 1, ThreadID: 5, Role: MessageRoleSystem, Content: "be brief", CreatedAt: created},
+		{ID: 2, ThreadID: 5, Role: MessageRoleUser, Content: "hi", Attachments: []Attachment{{FileID: "file-1", Role: AttachmentRoleData}}, CreatedAt: created},
+		{ID: 3, ThreadID: 5, Role: MessageRoleAssistant, Content: "hello", CreatedAt: created},
+	}
+	var imported CreateThreadOptions
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/threads/5":
+			_ = json.NewEncoder(w).Encode(Thread{ID: 5, Title: "Greetings", Metadata: map[string]any{"team": "support"}, CreatedAt: created})
+		case r.Method == "GET" && r.URL.Path == "/threads/5/messages":
+			_ = json.NewEncoder(w).Encode(ThreadMessagesResponse{Messages: messages, Total: len(messages)})
+		case r.Method == "POST" && r.URL.Path == "/threads":
+			_ = json.NewDecoder(r.Body).Decode(&imported)
+			_ = json.NewEncoder(w).Encode(Thread{ID: 6, Title: imported.Title})
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	client := NewClient(TaskForceAIOptions{BaseURL: server.URL})
+	ctx := context.Background()
+
+	var jsonl bytes.Buffer
+	if err := client.ExportThread(ctx, 5, ExportFormatJSONL, &jsonl); err != nil {
+		t.Fatalf("JSONL export failed: %v", err)
+	}
+	if lines := strings.Count(jsonl.String(), "\n"); lines != 3 {
+		t.Errorf("expected 3 JSONL lines, got %d", lines)
+	}
+
+	var md bytes.Buffer
+	if err := client.ExportThread(ctx, 5, ExportFormatMarkdown, &md); err != nil {
+		t.Fatalf("Markdown export failed: %v", err)
+	}
+	for _, want := range []string{"# Greetings", "- team: support", "## System (2026-01-02T03:04:05Z)", "`file-1` (data)"} {
+		if !strings.Contains(md.String(), want) {
+			t.Errorf("Markdown export missing %q:\n%s", want, md.String())
+		}
+	}
+
+	var exported bytes.Buffer
+	if err := client.ExportThread(ctx, 5, ExportFormatJSON, &exported); err != nil {
+		t.Fatalf("JSON export failed: %v", err)
+	}
+	thread, err := client.ImportThread(ctx, &exported)
+	if err != nil {
+		t.Fatalf("ImportThread failed: %v", err)
+	}
+	if thread.ID != 6 || imported.Title != "Greetings" || imported.Metadata["team"] != "support" || len(imported.Messages) != 3 {
+		t.Fatalf("unexpected import %+v", imported)
+	}
+	if m := imported.Messages[1]; m.ID != 0 || m.Content != "hi" || len(m.Attachments) != 1 || !m.CreatedAt.Equal(created) {
+		t.Errorf("message not preserved: %+v", m)
+	}
+
+	if err := client.ExportThread(ctx, 5, "xml", &exported); err == nil {
+		t.Error("expected error for unsupported format")
+	}
+}
@@ -3,33 +3,133 @@ package taskforceai
 import (
 	"context"
 	"encoding/json"
-	"fmt"
 	"io"
 	"mime/multipart"
-	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
 	"time"
 )
 
 // File represents an uploaded file.
 type File struct {
-	ID        string    `json:"id"`
-	Filename  string    `json:"filename"`
-	Purpose   string    `json:"purpose"`
-	Bytes     int64     `json:"bytes"`
-	CreatedAt time.Time `json:"created_at"`
-	MimeType  string    `json:"mime_type,omitempty"`
+	ID        string            `json:"id"`
+	Filename  string            `json:"filename"`
+	Purpose   string            `json:"purpose"`
+	Bytes     int64             `json:"bytes"`
+	CreatedAt time.Time         `json:"created_at"`
+	MimeType  string            `json:"mime_type,omitempty"`
+	Metadata  map[string]string `json:"metadata,omitempty"`
+}
+
+// FileMetadataSHA256 is the metadata key holding the hex SHA-256 digest of a
+// file's content, set by UploadFileDedup.
+const FileMetadataSHA256 = "sha256"
+
+// SHA256 returns the content digest recorded in the file's metadata, if any.
+func (f File) SHA256() string {
+	return f.Metadata[FileMetadataSHA256]
 }
 
 // FileUploadOptions contains options for uploading a file.
 type FileUploadOptions struct {
-	Purpose  string `json:"purpose,omitempty"` // e.g., "assistants", "fine-tune"
-	MimeType string `json:"mime_type,omitempty"`
+	Purpose  string            `json:"purpose,omitempty"` // e.g., "assistants", "fine-tune"
+	MimeType string            `json:"mime_type,omitempty"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+
+	// Progress, if set, is called as the upload advances.
+	Progress ProgressFunc `json:"-"`
+	// ProgressInterval is the minimum time between progress reports (default: 250ms).
+	ProgressInterval time.Duration `json:"-"`
+}
+
+// DownloadOptions contains options for downloading a file.
+type DownloadOptions struct {
+	// Progress, if set, is called as the download advances. The total size
+	// comes from the response's Content-Length.
+	Progress ProgressFunc
+	// ProgressInterval is the minimum time between progress reports (default: 250ms).
+	ProgressInterval time.Duration
 }
 
 // FileListResponse contains a list of files.
 type FileListResponse struct {
-	Files []File `json:"files"`
-	Total int    `json:"total"`
+	Files      []File `json:"files"`
+	Total      int    `json:"total"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// FileSortField is a field files can be sorted by.
+type FileSortField string
+
+const (
+	FileSortCreatedAt FileSortField = "created_at"
+	FileSortFilename  FileSortField = "filename"
+	FileSortBytes     FileSortField = "bytes"
+)
+
+// SortOrder is the direction of a sorted listing.
+type SortOrder string
+
+const (
+	SortAsc  SortOrder = "asc"
+	SortDesc SortOrder = "desc"
+)
+
+// ListFilesOptions filters and sorts a file listing. Zero values are omitted.
+type ListFilesOptions struct {
+	Limit          int
+	Offset         int
+	Cursor         string // from FileListResponse.NextCursor; takes precedence over Offset
+	Purpose        string
+	MimeType       string
+	FilenamePrefix string
+	Search         string // substring match on the filename
+	CreatedAfter   time.Time
+	CreatedBefore  time.Time
+	SortBy         FileSortField
+	Order          SortOrder
+}
+
+func (o *ListFilesOptions) query() url.Values {
+	q := url.Values{}
+	if o == nil {
+		return q
+	}
+	if o.Limit > 0 {
+		q.Set("limit", strconv.Itoa(o.Limit))
+	}
+	if o.Cursor != "" {
+		q.Set("cursor", o.Cursor)
+	} else if o.Offset > 0 {
+		q.Set("offset", strconv.Itoa(o.Offset))
+	}
+	if o.Purpose != "" {
+		q.Set("purpose", o.Purpose)
+	}
+	if o.MimeType != "" {
+		q.Set("mime_type", o.MimeType)
+	}
+	if o.FilenamePrefix != "" {
+		q.Set("filename_prefix", o.FilenamePrefix)
+	}
+	if o.Search != "" {
+		q.Set("search", o.Search)
+	}
+	if !o.CreatedAfter.IsZero() {
+		q.Set("created_after", o.CreatedAfter.UTC().Format(time.RFC3339))
+	}
+	if !o.CreatedBefore.IsZero() {
+		q.Set("created_before", o.CreatedBefore.UTC().Format(time.RFC3339))
+	}
+	if o.SortBy != "" {
+		q.Set("sort", string(o.SortBy))
+	}
+	if o.Order != "" {
+		q.Set("order", string(o.Order))
+	}
+	return q
 }
 
 // UploadFile uploads a file to the API.
@@ -37,6 +137,11 @@ func (c *Client) UploadFile(ctx context.Context, filename string, content io.Rea
 	pr, pw := io.Pipe()
 	writer := multipart.NewWriter(pw)
 
+	var tracker *progressTracker
+	if opts != nil {
+		tracker = newProgressTracker(opts.Progress, opts.ProgressInterval, readerSize(content))
+	}
+
 	go func() {
 		defer pw.Close()
 		defer writer.Close()
@@ -47,7 +152,7 @@ func (c *Client) UploadFile(ctx context.Context, filename string, content io.Rea
 			return
 		}
 
-		if _, err := io.Copy(part, content); err != nil {
+		if _, err := io.Copy(part, &progressReader{r: content, tracker: tracker}); err != nil {
 			pw.CloseWithError(err)
 			return
 		}
@@ -59,59 +164,57 @@ func (c *Client) UploadFile(ctx context.Context, filename string, content io.Rea
 			if opts.MimeType != "" {
 				writer.WriteField("mime_type", opts.MimeType)
 			}
+			if len(opts.Metadata) > 0 {
+				metadata, err := json.Marshal(opts.Metadata)
+				if err != nil {
+					pw.CloseWithError(err)
+					return
+				}
+				writer.WriteField("metadata", string(metadata))
+			}
 		}
 	}()
 
-	url := c.baseURL + "/files"
-	req, err := http.NewRequestWithContext(ctx, "POST", url, pr)
+	req, err := c.newRequest(ctx, "POST", "/files", pr)
 	if err != nil {
 		return nil, err
 	}
-
 	req.Header.Set("Content-Type", writer.FormDataContentType())
-	if c.apiKey != "" {
-		req.Header.Set("Authorization", "Bearer "+c.apiKey)
-	}
-	req.Header.Set("X-SDK-Language", "go")
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.send(req)
 	if err != nil {
 		return nil, err
 	}
-	defer func() { _ = resp.Body.Close() }()
-
-	if c.responseHook != nil {
-		c.responseHook(resp.StatusCode, resp.Header)
-	}
+	defer closeResponse(resp)
 
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return nil, fmt.Errorf("failed to upload file: status %d", resp.StatusCode)
+	if err := checkResponse(resp, "upload file"); err != nil {
+		return nil, err
 	}
 
 	var file File
-	if err := json.NewDecoder(resp.Body).Decode(&file); err != nil {
+	if err := c.decodeResponse(resp, &file); err != nil {
 		return nil, err
 	}
+	tracker.finish()
 
 	return &file, nil
 }
 
-// ListFiles retrieves a list of uploaded files.
+// ListFiles retrieves a list of uploaded files. A zero limit or offset is
+// left out of the request, so the server's defaults apply.
 func (c *Client) ListFiles(ctx context.Context, limit, offset int) (*FileListResponse, error) {
-	path := fmt.Sprintf("/files?limit=%d&offset=%d", limit, offset)
-
-	resp, err := c.doRequest(ctx, "GET", path, nil)
-	if err != nil {
-		return nil, err
-	}
-	defer func() { _ = resp.Body.Close() }()
+	return c.ListFilesWithOptions(ctx, &ListFilesOptions{Limit: limit, Offset: offset})
+}
 
-	if resp.StatusCode != 200 {
-		return nil, fmt.Errorf("failed to list files: status %d", resp.StatusCode)
+// ListFilesWithOptions retrieves a filtered, sorted list of uploaded files.
+func (c *Client) ListFilesWithOptions(ctx context.Context, opts *ListFilesOptions) (*FileListResponse, error) {
+	path := "/files"
+	if q := opts.query(); len(q) > 0 {
+		path += "?" + q.Encode()
 	}
 
-	var result FileListResponse
-	if err := decodeJSON(resp.Body, &result); err != nil {
+	result, err := do[FileListResponse](ctx, c, "GET", path, nil, "list files")
+	if err != nil {
 		return nil, err
 	}
 
@@ -122,43 +225,62 @@ func (c *Client) ListFiles(ctx context.Context, limit, offset int) (*FileListRes
 func (c *Client) GetFile(ctx context.Context, fileID string) (*File, error) {
 	path := "/files/" + fileID
 
-	resp, err := c.doRequest(ctx, "GET", path, nil)
+	file, err := do[File](ctx, c, "GET", path, nil, "get file")
 	if err != nil {
 		return nil, err
 	}
-	defer func() { _ = resp.Body.Close() }()
 
-	if resp.StatusCode != 200 {
-		return nil, fmt.Errorf("failed to get file: status %d", resp.StatusCode)
-	}
+	return &file, nil
+}
 
-	var file File
-	if err := decodeJSON(resp.Body, &file); err != nil {
-		return nil, err
+// listFilesPageSize is the page size used when walking every file.
+const listFilesPageSize = 100
+
+// listAllFiles pages through the files matching filter and returns all of
+// them. Limit, Offset and Cursor in filter are ignored.
+func (c *Client) listAllFiles(ctx context.Context, filter *ListFilesOptions) ([]File, error) {
+	opts := ListFilesOptions{}
+	if filter != nil {
+		opts = *filter
 	}
+	opts.Limit, opts.Offset, opts.Cursor = listFilesPageSize, 0, ""
 
-	return &file, nil
+	var all []File
+	for {
+		page, err := c.ListFilesWithOptions(ctx, &opts)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, page.Files...)
+		if page.NextCursor != "" {
+			opts.Cursor = page.NextCursor
+			continue
+		}
+		if opts.Cursor != "" {
+			return all, nil
+		}
+		opts.Offset += len(page.Files)
+		if len(page.Files) < listFilesPageSize || (page.Total > 0 && opts.Offset >= page.Total) {
+			return all, nil
+		}
+	}
 }
 
 // DeleteFile deletes a file by ID.
 func (c *Client) DeleteFile(ctx context.Context, fileID string) error {
 	path := "/files/" + fileID
 
-	resp, err := c.doRequest(ctx, "DELETE", path, nil)
-	if err != nil {
-		return err
-	}
-	defer func() { _ = resp.Body.Close() }()
-
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return fmt.Errorf("failed to delete file: status %d", resp.StatusCode)
-	}
-
-	return nil
+	return doNoContent(ctx, c, "DELETE", path, nil, "delete file")
 }
 
 // DownloadFile downloads the content of a file.
 func (c *Client) DownloadFile(ctx context.Context, fileID string) (io.ReadCloser, error) {
+	return c.DownloadFileWithOptions(ctx, fileID, nil)
+}
+
+// DownloadFileWithOptions downloads the content of a file with progress
+// reporting. The final report is sent when the returned reader hits EOF.
+func (c *Client) DownloadFileWithOptions(ctx context.Context, fileID string, opts *DownloadOptions) (io.ReadCloser, error) {
 	path := "/files/" + fileID + "/content"
 
 	resp, err := c.doRequest(ctx, "GET", path, nil)
@@ -166,15 +288,37 @@ func (c *Client) DownloadFile(ctx context.Context, fileID string) (io.ReadCloser
 		return nil, err
 	}
 
-	if resp.StatusCode != 200 {
-		resp.Body.Close()
-		return nil, fmt.Errorf("failed to download file: status %d", resp.StatusCode)
+	if err := checkResponse(resp, "download file"); err != nil {
+		closeResponse(resp)
+		return nil, err
+	}
+
+	if opts != nil && opts.Progress != nil {
+		tracker := newProgressTracker(opts.Progress, opts.ProgressInterval, resp.ContentLength)
+		return &progressReadCloser{
+			progressReader: progressReader{r: resp.Body, tracker: tracker},
+			closer:         resp.Body,
+		}, nil
 	}
 
 	return resp.Body, nil
 }
 
-// Helper function to decode JSON responses
-func decodeJSON(r io.Reader, v interface{}) error {
-	return json.NewDecoder(r).Decode(v)
+// writeFileAtomic replaces path with data via a temporary file in the same
+// directory, so readers never observe a partially written file.
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
 }
@@ -0,0 +1,101 @@
+package taskforceai
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestClient_ListFilesWithOptions(t *testing.T) {
+	var query url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" || r.URL.Path != "/files" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		query = r.URL.Query()
+		_ = json.NewEncoder(w).Encode(FileListResponse{Files: []File{{ID: "file-1"}}, Total: 1})
+	}))
+	defer server.Close()
+	client := NewClient(TaskForceAIOptions{BaseURL: server.URL})
+
+	after := time.Date(2026, 4, 1, 12, 0, 0, 0, time.FixedZone("CEST", 2*60*60))
+	_, err := client.ListFilesWithOptions(context.Background(), &ListFilesOptions{
+		Limit:          20,
+		Offset:         40,
+		Purpose:        "assistants",
+		MimeType:       "text/csv",
+		FilenamePrefix: "kb/",
+		Search:         "q3 report",
+		CreatedAfter:   after,
+		SortBy:         FileSortCreatedAt,
+		Order:          SortAsc,
+	})
+	if err != nil {
+		t.Fatalf("ListFilesWithOptions failed: %v", err)
+	}
+	want := url.Values{
+		"limit":           {"20"},
+		"offset":          {"40"},
+		"purpose":         {"assistants"},
+		"mime_type":       {"text/csv"},
+		"filename_prefix": {"kb/"},
+		"search":          {"q3 report"},
+		"created_after":   {"2026-04-01T10:00:00Z"},
+		"sort":            {"created_at"},
+		"order":           {"asc"},
+	}
+	if query.Encode() != want.Encode() {
+		t.Errorf("unexpected query %v", query)
+	}
+
+	// A cursor replaces the offset.
+	if _, err := client.ListFilesWithOptions(context.Background(), &ListFilesOptions{Offset: 40, Cursor: "c2"}); err != nil {
+		t.Fatal(err)
+	}
+	if query.Get("cursor") != "c2" || query.Has("offset") {
+		t.Errorf("unexpected cursor query %v", query)
+	}
+
+	// Zero values are omitted, so ListFiles(ctx, 0, 0) uses the server defaults.
+	if _, err := client.ListFiles(context.Background(), 0, 0); err != nil {
+		t.Fatal(err)
+	}
+	if len(query) != 0 {
+		t.Errorf("expected no query parameters, got %v", query)
+	}
+}
+
+func TestClient_ListAllFiles_Cursor(t *testing.T) {
+	var queries []url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.Query())
+		resp := FileListResponse{Files: []File{{ID: "file-1"}}, NextCursor: "c2"}
+		switch r.URL.Query().Get("cursor") {
+		case "c2":
+			resp = FileListResponse{Files: []File{{ID: "file-2"}}, NextCursor: "c3"}
+		case "c3":
+			resp = FileListResponse{Files: []File{{ID: "file-3"}}}
+		}
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+	client := NewClient(TaskForceAIOptions{BaseURL: server.URL})
+
+	files, err := client.listAllFiles(context.Background(), &ListFilesOptions{Purpose: "assistants", Offset: 7, Cursor: "ignored"})
+	if err != nil {
+		t.Fatalf("listAllFiles failed: %v", err)
+	}
+	if len(files) != 3 || files[2].ID != "file-3" || len(queries) != 3 {
+		t.Fatalf("unexpected files %+v after %d requests", files, len(queries))
+	}
+	if queries[0].Has("cursor") || queries[0].Has("offset") || queries[0].Get("limit") != "100" {
+		t.Errorf("unexpected first query %v", queries[0])
+	}
+	if queries[2].Get("cursor") != "c3" || queries[2].Get("purpose") != "assistants" {
+		t.Errorf("unexpected last query %v", queries[2])
+	}
+}
@@ -0,0 +1,128 @@
+package taskforceai
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+// Thread metadata keys recorded by ForkThread.
+const (
+	ThreadMetadataParentThreadID  = "parent_thread_id"
+	ThreadMetadataParentMessageID = "parent_message_id"
+)
+
+// ForkThreadOptions contains options for ForkThread.
+type ForkThreadOptions struct {
+	// Title of the new thread (default: the parent's title).
+	Title string
+	// Metadata for the new thread. The parent keys are added to it.
+	Metadata map[string]any
+}
+
+// ForkThread creates a new thread containing the history of threadID up to
+// and including atMessageID. The parent thread and message are recorded in
+// the new thread's metadata (see ThreadParent). The server forks the thread
+// if it supports it; otherwise the history is copied by the client.
+func (c *Client) ForkThread(ctx context.Context, threadID, atMessageID int, opts *ForkThreadOptions) (*Thread, error) {
+	o := ForkThreadOptions{}
+	if opts != nil {
+		o = *opts
+	}
+
+	metadata := make(map[string]any, len(o.Metadata)+2)
+	for k, v := range o.Metadata {
+		metadata[k] = v
+	}
+	metadata[ThreadMetadataParentThreadID] = threadID
+	metadata[ThreadMetadataParentMessageID] = atMessageID
+
+	thread, err := c.forkThreadOnServer(ctx, threadID, atMessageID, o.Title, metadata)
+	if err != nil || thread != nil {
+		return thread, err
+	}
+
+	return c.forkThreadOnClient(ctx, threadID, atMessageID, o.Title, metadata)
+}
+
+// ThreadParent returns the parent thread and message recorded by ForkThread.
+func ThreadParent(t Thread) (threadID, messageID int, ok bool) {
+	threadID, ok1 := metadataInt(t.Metadata[ThreadMetadataParentThreadID])
+	messageID, ok2 := metadataInt(t.Metadata[ThreadMetadataParentMessageID])
+	if !ok1 || !ok2 {
+		return 0, 0, false
+	}
+	return threadID, messageID, true
+}
+
+// forkThreadOnServer returns nil if the server has no fork endpoint.
+func (c *Client) forkThreadOnServer(ctx context.Context, threadID, atMessageID int, title string, metadata map[string]any) (*Thread, error) {
+	path := fmt.Sprintf("/threads/%d/fork", threadID)
+	body := map[string]interface{}{
+		"message_id": atMessageID,
+		"metadata":   metadata,
+	}
+	if title != "" {
+		body["title"] = title
+	}
+
+	thread, err := do[Thread](ctx, c, "POST", path, body, "fork thread")
+	if isStatus(err, http.StatusNotFound, http.StatusMethodNotAllowed, http.StatusNotImplemented) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &thread, nil
+}
+
+func (c *Client) forkThreadOnClient(ctx context.Context, threadID, atMessageID int, title string, metadata map[string]any) (*Thread, error) {
+	if title == "" {
+		parent, err := c.GetThread(ctx, threadID)
+		if err != nil {
+			return nil, err
+		}
+		title = parent.Title
+	}
+
+	messages, err := c.listAllThreadMessages(ctx, threadID)
+	if err != nil {
+		return nil, err
+	}
+
+	end := -1
+	for i, m := range messages {
+		if m.ID == atMessageID {
+			end = i
+			break
+		}
+	}
+	if end < 0 {
+		return nil, fmt.Errorf("message %d not found in thread %d", atMessageID, threadID)
+	}
+
+	history := make([]ThreadMessage, end+1)
+	for i, m := range messages[:end+1] {
+		m.ID, m.ThreadID = 0, 0
+		history[i] = m
+	}
+
+	return c.CreateThread(ctx, &CreateThreadOptions{Title: title, Messages: history, Metadata: metadata})
+}
+
+// metadataInt converts a decoded JSON metadata value to an int.
+func metadataInt(v any) (int, bool) {
+	switch n := v.(type) {
+	case int:
+		return n, true
+	case float64:
+		return int(n), n == float64(int(n))
+	case string:
+		i, err := strconv.Atoi(n)
+		return i, err == nil
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,75 @@
+package taskforceai
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClient_ForkThread_ClientSide(t *testing.T) {
+	var created CreateThreadOptions
+	var raw map[string]json.RawMessage
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/threads/2/fork" || r.URL.Path == "/threads/5/fork":
+			w.WriteHeader(http.StatusNotFound)
+		case r.URL.Path == "/threads/5/messages":
+			http.Error(w, `{"error": "thread not found"}`, http.StatusNotFound)
+		case r.URL.Path == "/threads/2":
+			_ = json.NewEncoder(w).Encode(Thread{ID: 2, Title: "ideas"})
+		case r.URL.Path == "/threads/2/messages":
+			_ = json.NewEncoder(w).Encode(ThreadMessagesResponse{Messages: []ThreadMessage{
+				{ID: 10, ThreadID: 2, Role: MessageRoleUser, Content: "q1"},
+				{ID: 11, ThreadID: 2, Role: MessageRoleAssistant, Content: "a1"},
+				{ID: 12, ThreadID: 2, Role: MessageRoleUser, Content: "q2"},
+			}, Total: 3})
+		case r.Method == "POST" && r.URL.Path == "/threads":
+			body, _ := io.ReadAll(r.Body)
+			_ = json.Unmarshal(body, &created)
+			_ = json.Unmarshal(body, &raw)
+			_ = json.NewEncoder(w).Encode(Thread{ID: 3, Title: created.Title, Metadata: created.Metadata})
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	client := NewClient(TaskForceAIOptions{BaseURL: server.URL})
+
+	thread, err := client.ForkThread(context.Background(), 2, 11, &ForkThreadOptions{Metadata: map[string]any{"branch": "b"}})
+	if err != nil {
+		t.Fatalf("ForkThread failed: %v", err)
+	}
+	if len(created.Messages) != 2 || created.Messages[1].Content != "a1" || created.Messages[1].ID != 0 {
+		t.Errorf("unexpected forked history %+v", created.Messages)
+	}
+	var history []map[string]any
+	_ = json.Unmarshal(raw["messages"], &history)
+	for _, m := range history {
+		if _, ok := m["id"]; ok {
+			t.Errorf("copied message sent with an id: %v", m)
+		}
+		if _, ok := m["thread_id"]; ok {
+			t.Errorf("copied message sent with a thread_id: %v", m)
+		}
+	}
+	if thread.Title != "ideas" || thread.Metadata["branch"] != "b" {
+		t.Errorf("unexpected thread %+v", thread)
+	}
+	if parent, message, ok := ThreadParent(*thread); !ok || parent != 2 || message != 11 {
+		t.Errorf("ThreadParent = %d, %d, %v", parent, message, ok)
+	}
+
+	if _, err := client.ForkThread(context.Background(), 2, 99, nil); err == nil {
+		t.Error("expected error for unknown message")
+	}
+
+	// A missing parent is still reported after falling back to the client.
+	_, err = client.ForkThread(context.Background(), 5, 1, &ForkThreadOptions{Title: "copy"})
+	if !isStatus(err, http.StatusNotFound) {
+		t.Errorf("expected 404 error, got %v", err)
+	}
+}
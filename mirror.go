@@ -0,0 +1,279 @@
+package taskforceai
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// DefaultMirrorArchiveDir is the directory, relative to the mirror, that
+// receives the files of deleted threads.
+const DefaultMirrorArchiveDir = "archive"
+
+// MirrorOptions contains options for MirrorThreads and RunThreadMirror.
+type MirrorOptions struct {
+	// ArchiveDir receives the files of deleted threads. A relative path is
+	// resolved against the mirror directory (default: "archive").
+	ArchiveDir string
+}
+
+// MirrorReport summarizes a mirror run.
+type MirrorReport struct {
+	Written   []string `json:"written"` // files created or rewritten
+	Unchanged int      `json:"unchanged"`
+	Archived  []string `json:"archived"` // archive paths of deleted threads
+}
+
+// mirroredFile is a thread file found in the mirror directory.
+type mirroredFile struct {
+	path      string
+	updatedAt time.Time
+}
+
+// MirrorThreads mirrors every thread into dir as one Markdown file with YAML
+// front matter (id, title, timestamps, metadata) followed by the transcript.
+// Only threads whose UpdatedAt differs from the file's front matter are
+// fetched and rewritten; files of deleted threads are moved to the archive
+// directory. Files without a thread id in their front matter are left
+// alone. Failures on individual threads are returned together.
+func (c *Client) MirrorThreads(ctx context.Context, dir string, opts *MirrorOptions) (*MirrorReport, error) {
+	o := MirrorOptions{}
+	if opts != nil {
+		o = *opts
+	}
+	archiveDir := o.ArchiveDir
+	if archiveDir == "" {
+		archiveDir = DefaultMirrorArchiveDir
+	}
+	if !filepath.IsAbs(archiveDir) {
+		archiveDir = filepath.Join(dir, archiveDir)
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	existing, err := scanMirror(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	threads, err := c.listAllThreads(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &MirrorReport{}
+	var errs []error
+	seen := make(map[int]bool, len(threads))
+
+	for _, thread := range threads {
+		seen[thread.ID] = true
+		path := filepath.Join(dir, mirrorFilename(thread))
+
+		current, ok := existing[thread.ID]
+		if ok && current.path == path && current.updatedAt.Equal(thread.UpdatedAt) {
+			report.Unchanged++
+			continue
+		}
+
+		if err := c.mirrorThread(ctx, thread, path); err != nil {
+			errs = append(errs, fmt.Errorf("thread %d: %w", thread.ID, err))
+			continue
+		}
+		if ok && current.path != path {
+			// The title changed; drop the file under the old name.
+			if err := os.Remove(current.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+				errs = append(errs, fmt.Errorf("thread %d: %w", thread.ID, err))
+			}
+		}
+		report.Written = append(report.Written, path)
+	}
+
+	for id, file := range existing {
+		if seen[id] {
+			continue
+		}
+		archived, err := archiveMirrorFile(file.path, archiveDir)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("thread %d: %w", id, err))
+			continue
+		}
+		report.Archived = append(report.Archived, archived)
+	}
+
+	return report, errors.Join(errs...)
+}
+
+// RunThreadMirror mirrors the threads immediately and then every interval
+// until ctx is cancelled, passing each result to onReport (which may be
+// nil).
+func (c *Client) RunThreadMirror(ctx context.Context, dir string, interval time.Duration, opts *MirrorOptions, onReport func(*MirrorReport, error)) error {
+	if interval <= 0 {
+		return fmt.Errorf("interval must be positive")
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		report, err := c.MirrorThreads(ctx, dir, opts)
+		if onReport != nil {
+			onReport(report, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func (c *Client) mirrorThread(ctx context.Context, thread Thread, path string) error {
+	messages, err := c.listAllThreadMessages(ctx, thread.ID)
+	if err != nil {
+		return err
+	}
+	export := &ThreadExport{Thread: thread, Messages: messages}
+
+	var buf bytes.Buffer
+	buf.WriteString("---\n")
+	fmt.Fprintf(&buf, "id: %d\n", thread.ID)
+	fmt.Fprintf(&buf, "title: %s\n", yamlValue(thread.Title))
+	fmt.Fprintf(&buf, "created_at: %s\n", thread.CreatedAt.Format(time.RFC3339Nano))
+	fmt.Fprintf(&buf, "updated_at: %s\n", thread.UpdatedAt.Format(time.RFC3339Nano))
+	if len(thread.Metadata) > 0 {
+		buf.WriteString("metadata:\n")
+		for _, k := range sortedKeys(thread.Metadata) {
+			fmt.Fprintf(&buf, "  %s: %s\n", yamlValue(k), yamlValue(thread.Metadata[k]))
+		}
+	}
+	buf.WriteString("---\n\n")
+	fmt.Fprintf(&buf, "# %s\n", export.title())
+	export.writeMarkdownMessages(&buf)
+
+	return writeFileAtomic(path, buf.Bytes())
+}
+
+// yamlValue encodes v as JSON, which is valid YAML flow syntax.
+func yamlValue(v any) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return strconv.Quote(fmt.Sprint(v))
+	}
+	return string(data)
+}
+
+// mirrorFilename returns "<slugified title>-<id>.md", or "thread-<id>.md"
+// for threads without a usable title.
+func mirrorFilename(thread Thread) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(thread.Title) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+			dash = false
+		} else if !dash && b.Len() > 0 {
+			b.WriteByte('-')
+			dash = true
+		}
+		if b.Len() >= 60 {
+			break
+		}
+	}
+	slug := strings.TrimRight(b.String(), "-")
+	if slug == "" {
+		slug = "thread"
+	}
+	return fmt.Sprintf("%s-%d.md", slug, thread.ID)
+}
+
+// scanMirror reads the front matter of the Markdown files in dir and
+// returns them keyed by thread ID.
+func scanMirror(dir string) (map[int]mirroredFile, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	files := map[int]mirroredFile{}
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".md" {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		id, updatedAt, err := readMirrorFrontMatter(path)
+		if err != nil {
+			return nil, err
+		}
+		if id != 0 {
+			files[id] = mirroredFile{path: path, updatedAt: updatedAt}
+		}
+	}
+	return files, nil
+}
+
+// readMirrorFrontMatter returns the id and updated_at of a mirrored file,
+// or a zero id if the file has no such front matter.
+func readMirrorFrontMatter(path string) (int, time.Time, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+	defer func() { _ = f.Close() }()
+
+	scanner := bufio.NewScanner(f)
+	if !scanner.Scan() || scanner.Text() != "---" {
+		return 0, time.Time{}, scanner.Err()
+	}
+
+	var id int
+	var updatedAt time.Time
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "---" {
+			return id, updatedAt, nil
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "id":
+			id, _ = strconv.Atoi(strings.TrimSpace(value))
+		case "updated_at":
+			updatedAt, _ = time.Parse(time.RFC3339Nano, strings.TrimSpace(value))
+		}
+	}
+	// Unterminated front matter.
+	return 0, time.Time{}, scanner.Err()
+}
+
+// archiveMirrorFile moves path into archiveDir, adding a timestamp to the
+// name if a file with the same name was archived before.
+func archiveMirrorFile(path, archiveDir string) (string, error) {
+	if err := os.MkdirAll(archiveDir, 0o755); err != nil {
+		return "", err
+	}
+
+	name := filepath.Base(path)
+	target := filepath.Join(archiveDir, name)
+	if _, err := os.Stat(target); err == nil {
+		ext := filepath.Ext(name)
+		target = filepath.Join(archiveDir, strings.TrimSuffix(name, ext)+"-"+time.Now().UTC().Format("20060102T150405")+ext)
+	}
+
+	if err := os.Rename(path, target); err != nil {
+		return "", err
+	}
+	return target, nil
+}
@@ -0,0 +1,83 @@
+package taskforceai
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestClient_MirrorThreads(t *testing.T) {
+	updated := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+	var mu sync.Mutex
+	threads := []Thread{
+		{ID: 1, Title: "Billing migration", Metadata: map[string]any{"team": "payments"}, UpdatedAt: updated},
+		{ID: 2, Title: "Lunch", UpdatedAt: updated},
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch {
+		case r.URL.Path == "/threads":
+			_ = json.NewEncoder(w).Encode(ThreadListResponse{Threads: threads, Total: len(threads)})
+		case strings.HasSuffix(r.URL.Path, "/messages"):
+			_ = json.NewEncoder(w).Encode(ThreadMessagesResponse{Messages: []ThreadMessage{{ID: 1, Role: MessageRoleUser, Content: "hello from " + r.URL.Path}}, Total: 1})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	client := NewClient(TaskForceAIOptions{BaseURL: server.URL})
+	dir := t.TempDir()
+	ctx := context.Background()
+
+	// Keep an unrelated note to make sure it is left alone.
+	if err := os.WriteFile(filepath.Join(dir, "notes.md"), []byte("# Notes\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := client.MirrorThreads(ctx, dir, nil)
+	if err != nil || len(report.Written) != 2 {
+		t.Fatalf("initial mirror: %+v, %v", report, err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "billing-migration-1.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"id: 1\n", "title: \"Billing migration\"\n", "updated_at: 2026-05-01T09:00:00Z\n", "  \"team\": \"payments\"\n", "hello from /threads/1/messages"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("mirrored file missing %q:\n%s", want, data)
+		}
+	}
+
+	report, err = client.MirrorThreads(ctx, dir, nil)
+	if err != nil || len(report.Written) != 0 || report.Unchanged != 2 {
+		t.Fatalf("expected no changes: %+v, %v", report, err)
+	}
+
+	// Rename one thread and delete the other.
+	mu.Lock()
+	threads = []Thread{{ID: 1, Title: "Billing cutover", UpdatedAt: updated.Add(time.Hour)}}
+	mu.Unlock()
+
+	report, err = client.MirrorThreads(ctx, dir, nil)
+	if err != nil || len(report.Written) != 1 || len(report.Archived) != 1 {
+		t.Fatalf("unexpected report %+v, %v", report, err)
+	}
+	for _, name := range []string{"billing-cutover-1.md", "notes.md", filepath.Join("archive", "lunch-2.md")} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Errorf("expected %s: %v", name, err)
+		}
+	}
+	for _, name := range []string{"billing-migration-1.md", "lunch-2.md"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); !os.IsNotExist(err) {
+			t.Errorf("expected %s to be gone", name)
+		}
+	}
+}
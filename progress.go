@@ -0,0 +1,194 @@
+package taskforceai
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultProgressInterval is the default minimum time between progress reports.
+const DefaultProgressInterval = 250 * time.Millisecond
+
+// TransferProgress describes the state of an upload or download.
+type TransferProgress struct {
+	BytesTransferred int64
+	TotalBytes       int64 // -1 when the size is unknown
+	BytesPerSecond   float64
+	Elapsed          time.Duration
+	Done             bool
+}
+
+// ProgressFunc receives transfer progress reports. Calls are serialized.
+type ProgressFunc func(progress TransferProgress)
+
+// progressTracker accumulates transferred bytes and reports them at most once
+// per interval. It is safe for concurrent use. The callback runs without the
+// state lock held, so bytes keep being counted while it runs; reports are
+// serialized and a stale one is never sent after a newer one.
+type progressTracker struct {
+	fn       ProgressFunc
+	interval time.Duration
+	total    int64
+	base     int64 // bytes already transferred before this attempt started
+	start    time.Time
+
+	mu         sync.Mutex
+	current    int64
+	lastReport time.Time
+	done       bool
+	seq        uint64 // number of reports taken
+
+	reportMu sync.Mutex
+	sent     uint64 // seq of the last report delivered
+}
+
+// newProgressTracker returns nil when fn is nil; all methods accept a nil
+// receiver so callers need not check.
+func newProgressTracker(fn ProgressFunc, interval time.Duration, total int64) *progressTracker {
+	if fn == nil {
+		return nil
+	}
+	if interval <= 0 {
+		interval = DefaultProgressInterval
+	}
+	return &progressTracker{fn: fn, interval: interval, total: total, start: time.Now()}
+}
+
+// resume records bytes that were transferred by an earlier attempt so they
+// count towards the total but not towards throughput.
+func (t *progressTracker) resume(n int64) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	t.base += n
+	t.current += n
+	t.mu.Unlock()
+}
+
+func (t *progressTracker) add(n int64) {
+	if t == nil || n == 0 {
+		return
+	}
+	t.mu.Lock()
+	t.current += n
+	now := time.Now()
+	if t.done || now.Sub(t.lastReport) < t.interval {
+		t.mu.Unlock()
+		return
+	}
+	p, seq := t.snapshotLocked(now)
+	t.mu.Unlock()
+
+	t.send(p, seq)
+}
+
+// finish sends the final report. Only the first call has an effect.
+func (t *progressTracker) finish() {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	if t.done {
+		t.mu.Unlock()
+		return
+	}
+	t.done = true
+	if t.total < 0 {
+		t.total = t.current
+	}
+	p, seq := t.snapshotLocked(time.Now())
+	t.mu.Unlock()
+
+	t.send(p, seq)
+}
+
+func (t *progressTracker) snapshotLocked(now time.Time) (TransferProgress, uint64) {
+	t.lastReport = now
+	t.seq++
+	elapsed := now.Sub(t.start)
+
+	var rate float64
+	if elapsed > 0 {
+		rate = float64(t.current-t.base) / elapsed.Seconds()
+	}
+
+	return TransferProgress{
+		BytesTransferred: t.current,
+		TotalBytes:       t.total,
+		BytesPerSecond:   rate,
+		Elapsed:          elapsed,
+		Done:             t.done,
+	}, t.seq
+}
+
+// send delivers report seq unless a newer one has already been delivered.
+func (t *progressTracker) send(p TransferProgress, seq uint64) {
+	t.reportMu.Lock()
+	defer t.reportMu.Unlock()
+
+	if seq <= t.sent {
+		return
+	}
+	t.sent = seq
+	t.fn(p)
+}
+
+// progressReader counts bytes read through it.
+type progressReader struct {
+	r       io.Reader
+	tracker *progressTracker
+	n       atomic.Int64
+}
+
+func (p *progressReader) Read(b []byte) (int, error) {
+	n, err := p.r.Read(b)
+	p.n.Add(int64(n))
+	p.tracker.add(int64(n))
+	return n, err
+}
+
+// progressReadCloser reports download progress and finishes on EOF.
+type progressReadCloser struct {
+	progressReader
+	closer io.Closer
+}
+
+func (p *progressReadCloser) Read(b []byte) (int, error) {
+	n, err := p.progressReader.Read(b)
+	if err == io.EOF {
+		p.tracker.finish()
+	}
+	return n, err
+}
+
+func (p *progressReadCloser) Close() error {
+	return p.closer.Close()
+}
+
+// readerSize returns the number of bytes remaining in r, or -1 if unknown.
+func readerSize(r io.Reader) int64 {
+	switch v := r.(type) {
+	case *bytes.Reader:
+		return int64(v.Len())
+	case *bytes.Buffer:
+		return int64(v.Len())
+	case *strings.Reader:
+		return int64(v.Len())
+	case *os.File:
+		info, err := v.Stat()
+		if err != nil || !info.Mode().IsRegular() {
+			return -1
+		}
+		pos, err := v.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return -1
+		}
+		return info.Size() - pos
+	}
+	return -1
+}
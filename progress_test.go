@@ -0,0 +1,110 @@
+package taskforceai
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestProgressTracker_CallbackOutsideLock(t *testing.T) {
+	entered := make(chan struct{})
+	release := make(chan struct{})
+	var once sync.Once
+	tracker := newProgressTracker(func(TransferProgress) {
+		once.Do(func() {
+			close(entered)
+			<-release
+		})
+	}, time.Hour, 10)
+	defer close(release)
+
+	go tracker.add(1)
+	<-entered
+
+	done := make(chan struct{})
+	go func() {
+		tracker.add(1)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("add blocked while the progress callback was running")
+	}
+}
+
+func TestClient_UploadFile_Progress(t *testing.T) {
+	content := bytes.Repeat([]byte("x"), 64<<10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(io.Discard, r.Body)
+		_, _ = w.Write([]byte(`{"id": "file-1", "bytes": ` + strconv.Itoa(len(content)) + `}`))
+	}))
+	defer server.Close()
+	client := NewClient(TaskForceAIOptions{BaseURL: server.URL})
+
+	var reports []TransferProgress
+	_, err := client.UploadFile(context.Background(), "data.bin", bytes.NewReader(content), &FileUploadOptions{
+		Progress:         func(p TransferProgress) { reports = append(reports, p) },
+		ProgressInterval: time.Nanosecond,
+	})
+	if err != nil {
+		t.Fatalf("UploadFile failed: %v", err)
+	}
+
+	if len(reports) < 2 {
+		t.Fatalf("expected intermediate and final reports, got %+v", reports)
+	}
+	last := reports[len(reports)-1]
+	if !last.Done || last.BytesTransferred != int64(len(content)) || last.TotalBytes != int64(len(content)) {
+		t.Errorf("unexpected final report %+v", last)
+	}
+	for i, p := range reports[:len(reports)-1] {
+		if p.Done || (i > 0 && p.BytesTransferred < reports[i-1].BytesTransferred) {
+			t.Errorf("unexpected report %d: %+v", i, p)
+		}
+	}
+}
+
+func TestClient_DownloadFileWithOptions_Progress(t *testing.T) {
+	content := bytes.Repeat([]byte("y"), 64<<10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(content)
+	}))
+	defer server.Close()
+	client := NewClient(TaskForceAIOptions{BaseURL: server.URL})
+
+	var reports []TransferProgress
+	rc, err := client.DownloadFileWithOptions(context.Background(), "file-1", &DownloadOptions{
+		Progress:         func(p TransferProgress) { reports = append(reports, p) },
+		ProgressInterval: time.Hour,
+	})
+	if err != nil {
+		t.Fatalf("DownloadFileWithOptions failed: %v", err)
+	}
+	defer func() { _ = rc.Close() }()
+
+	// Nothing beyond the first report is sent before EOF with a long interval.
+	buf := make([]byte, 1024)
+	if _, err := io.ReadFull(rc, buf); err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range reports {
+		if p.Done {
+			t.Fatalf("final report sent before EOF: %+v", p)
+		}
+	}
+
+	if _, err := io.Copy(io.Discard, rc); err != nil {
+		t.Fatal(err)
+	}
+	last := reports[len(reports)-1]
+	if !last.Done || last.BytesTransferred != int64(len(content)) || last.TotalBytes != int64(len(content)) {
+		t.Errorf("unexpected final report %+v", last)
+	}
+}
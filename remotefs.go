@@ -0,0 +1,445 @@
+package taskforceai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultRemoteFSCacheTTL is how long RemoteFS reuses a file listing.
+	DefaultRemoteFSCacheTTL = 30 * time.Second
+)
+
+// RemoteFSOptions contains options for RemoteFS.
+type RemoteFSOptions struct {
+	// Context is used for every request made by the file system
+	// (default: context.Background()).
+	Context context.Context
+	// PurposeDirs places each file under a directory named after its purpose.
+	PurposeDirs bool
+	// CacheTTL is how long the file listing is reused before it is fetched
+	// again (default: 30s).
+	CacheTTL time.Duration
+}
+
+// RemoteFileSystem is a read-only fs.FS over uploaded files. Filenames
+// containing "/" form nested directories; when several files share a path,
+// the most recently created one is visible.
+type RemoteFileSystem struct {
+	client *Client
+	ctx    context.Context
+	opts   RemoteFSOptions
+
+	mu        sync.Mutex
+	fetchedAt time.Time
+	files     map[string]*File         // path -> file
+	dirs      map[string][]fs.DirEntry // path -> sorted entries
+}
+
+var (
+	_ fs.FS        = (*RemoteFileSystem)(nil)
+	_ fs.ReadDirFS = (*RemoteFileSystem)(nil)
+	_ fs.StatFS    = (*RemoteFileSystem)(nil)
+)
+
+// RemoteFS returns a file system view of the files uploaded with client. It
+// implements fs.FS, fs.ReadDirFS and fs.StatFS; opened files also implement
+// io.Seeker, so the view can be served with http.FileServer(http.FS(...)).
+func RemoteFS(client *Client, opts *RemoteFSOptions) *RemoteFileSystem {
+	o := RemoteFSOptions{}
+	if opts != nil {
+		o = *opts
+	}
+	if o.Context == nil {
+		o.Context = context.Background()
+	}
+	if o.CacheTTL <= 0 {
+		o.CacheTTL = DefaultRemoteFSCacheTTL
+	}
+	return &RemoteFileSystem{client: client, ctx: o.Context, opts: o}
+}
+
+// Invalidate drops the cached listing so the next call fetches it again.
+func (rfs *RemoteFileSystem) Invalidate() {
+	rfs.mu.Lock()
+	rfs.fetchedAt = time.Time{}
+	rfs.mu.Unlock()
+}
+
+// Open implements fs.FS.
+func (rfs *RemoteFileSystem) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+
+	file, entries, isDir, err := rfs.lookup(name)
+	if err != nil {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+	}
+	if isDir {
+		return &remoteDir{info: dirInfo(name), entries: entries}, nil
+	}
+
+	// The listed metadata is used until the content shows it is stale.
+	cached := *file
+	return &remoteFile{fsys: rfs, file: &cached, info: fileInfo{name: path.Base(name), file: &cached}}, nil
+}
+
+// Stat implements fs.StatFS.
+func (rfs *RemoteFileSystem) Stat(name string) (fs.FileInfo, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "stat", Path: name, Err: fs.ErrInvalid}
+	}
+
+	file, _, isDir, err := rfs.lookup(name)
+	if err != nil {
+		return nil, &fs.PathError{Op: "stat", Path: name, Err: err}
+	}
+	if isDir {
+		return dirInfo(name), nil
+	}
+	return fileInfo{name: path.Base(name), file: file}, nil
+}
+
+// ReadDir implements fs.ReadDirFS.
+func (rfs *RemoteFileSystem) ReadDir(name string) ([]fs.DirEntry, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrInvalid}
+	}
+
+	_, entries, isDir, err := rfs.lookup(name)
+	if err != nil {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: err}
+	}
+	if !isDir {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: errors.New("not a directory")}
+	}
+	return append([]fs.DirEntry(nil), entries...), nil
+}
+
+func (rfs *RemoteFileSystem) lookup(name string) (*File, []fs.DirEntry, bool, error) {
+	files, dirs, err := rfs.tree()
+	if err != nil {
+		return nil, nil, false, err
+	}
+	if entries, ok := dirs[name]; ok {
+		return nil, entries, true, nil
+	}
+	if file, ok := files[name]; ok {
+		return file, nil, false, nil
+	}
+	return nil, nil, false, fs.ErrNotExist
+}
+
+// tree returns the cached listing, fetching it first if it has expired. The
+// lock is not held during the fetch; the returned maps are never modified.
+func (rfs *RemoteFileSystem) tree() (map[string]*File, map[string][]fs.DirEntry, error) {
+	rfs.mu.Lock()
+	if !rfs.fetchedAt.IsZero() && time.Since(rfs.fetchedAt) < rfs.opts.CacheTTL {
+		files, dirs := rfs.files, rfs.dirs
+		rfs.mu.Unlock()
+		return files, dirs, nil
+	}
+	rfs.mu.Unlock()
+
+	all, err := rfs.client.listAllFiles(rfs.ctx, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	files, dirs := rfs.buildTree(all)
+
+	rfs.mu.Lock()
+	rfs.files, rfs.dirs, rfs.fetchedAt = files, dirs, time.Now()
+	rfs.mu.Unlock()
+	return files, dirs, nil
+}
+
+// buildTree maps the listed files to paths and derives their directories.
+func (rfs *RemoteFileSystem) buildTree(all []File) (map[string]*File, map[string][]fs.DirEntry) {
+	files := map[string]*File{}
+	for i := range all {
+		p := rfs.pathFor(&all[i])
+		if p == "" {
+			continue
+		}
+		if existing, ok := files[p]; !ok || all[i].CreatedAt.After(existing.CreatedAt) {
+			files[p] = &all[i]
+		}
+	}
+
+	// Every ancestor of a file is a directory. A file whose path is also a
+	// directory is hidden, since the directory takes precedence.
+	children := map[string]map[string]fs.DirEntry{".": {}}
+	for p := range files {
+		for dir := path.Dir(p); dir != "."; dir = path.Dir(dir) {
+			children[dir] = map[string]fs.DirEntry{}
+		}
+	}
+	for dir := range children {
+		delete(files, dir)
+	}
+
+	for p, file := range files {
+		children[path.Dir(p)][path.Base(p)] = fs.FileInfoToDirEntry(fileInfo{name: path.Base(p), file: file})
+	}
+	for dir := range children {
+		if dir != "." {
+			children[path.Dir(dir)][path.Base(dir)] = fs.FileInfoToDirEntry(dirInfo(dir))
+		}
+	}
+
+	dirs := make(map[string][]fs.DirEntry, len(children))
+	for dir, byName := range children {
+		entries := make([]fs.DirEntry, 0, len(byName))
+		for _, e := range byName {
+			entries = append(entries, e)
+		}
+		sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+		dirs[dir] = entries
+	}
+	return files, dirs
+}
+
+// pathFor returns the path of file in the view, or "" if it has none.
+func (rfs *RemoteFileSystem) pathFor(file *File) string {
+	name := strings.Trim(file.Filename, "/")
+	if rfs.opts.PurposeDirs && file.Purpose != "" {
+		name = file.Purpose + "/" + name
+	}
+	if name == "" || !fs.ValidPath(name) {
+		return ""
+	}
+	return name
+}
+
+// fileInfo is the fs.FileInfo of a remote file. Sys returns the *File.
+type fileInfo struct {
+	name string
+	file *File
+}
+
+func (fi fileInfo) Name() string       { return fi.name }
+func (fi fileInfo) Size() int64        { return fi.file.Bytes }
+func (fi fileInfo) Mode() fs.FileMode  { return 0o444 }
+func (fi fileInfo) ModTime() time.Time { return fi.file.CreatedAt }
+func (fi fileInfo) IsDir() bool        { return false }
+func (fi fileInfo) Sys() any           { return fi.file }
+
+// dirInfo is the fs.FileInfo of a directory implied by file paths.
+type dirInfo string
+
+func (di dirInfo) Name() string       { return path.Base(string(di)) }
+func (di dirInfo) Size() int64        { return 0 }
+func (di dirInfo) Mode() fs.FileMode  { return fs.ModeDir | 0o555 }
+func (di dirInfo) ModTime() time.Time { return time.Time{} }
+func (di dirInfo) IsDir() bool        { return true }
+func (di dirInfo) Sys() any           { return nil }
+
+// remoteDir is an open directory.
+type remoteDir struct {
+	info    dirInfo
+	entries []fs.DirEntry
+	offset  int
+}
+
+func (d *remoteDir) Stat() (fs.FileInfo, error) { return d.info, nil }
+func (d *remoteDir) Close() error               { return nil }
+
+func (d *remoteDir) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: string(d.info), Err: errors.New("is a directory")}
+}
+
+func (d *remoteDir) ReadDir(n int) ([]fs.DirEntry, error) {
+	remaining := d.entries[d.offset:]
+	if n <= 0 {
+		d.offset = len(d.entries)
+		return append([]fs.DirEntry(nil), remaining...), nil
+	}
+	if len(remaining) == 0 {
+		return nil, io.EOF
+	}
+	if n > len(remaining) {
+		n = len(remaining)
+	}
+	d.offset += n
+	return append([]fs.DirEntry(nil), remaining[:n]...), nil
+}
+
+// remoteFile is an open file. Content is downloaded lazily from the current
+// offset; seeking drops the open download and resumes with a Range request.
+// The size comes from the listing and is refreshed if the content disagrees.
+type remoteFile struct {
+	fsys   *RemoteFileSystem
+	file   *File
+	info   fileInfo
+	offset int64
+	body   io.ReadCloser
+	closed bool
+}
+
+func (f *remoteFile) Stat() (fs.FileInfo, error) { return f.info, nil }
+
+func (f *remoteFile) Read(b []byte) (int, error) {
+	if f.closed {
+		return 0, fs.ErrClosed
+	}
+	if f.offset >= f.file.Bytes {
+		return 0, io.EOF
+	}
+	if f.body == nil {
+		body, total, err := f.fsys.openContent(f.file.ID, f.offset)
+		if err != nil {
+			return 0, err
+		}
+		f.body = body
+		if total >= 0 && total != f.file.Bytes {
+			if err := f.refresh(); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	n, err := f.body.Read(b)
+	f.offset += int64(n)
+	if err == io.EOF && f.offset < f.file.Bytes {
+		// The listed size may be stale.
+		if rerr := f.refresh(); rerr != nil {
+			return n, rerr
+		}
+		if f.offset < f.file.Bytes {
+			err = io.ErrUnexpectedEOF
+		}
+	}
+	return n, err
+}
+
+// refresh replaces the file's metadata with the server's and drops the
+// file system's cached listing.
+func (f *remoteFile) refresh() error {
+	fresh, err := f.fsys.client.GetFile(f.fsys.ctx, f.file.ID)
+	if err != nil {
+		return err
+	}
+	*f.file = *fresh
+	f.fsys.Invalidate()
+	return nil
+}
+
+func (f *remoteFile) Seek(offset int64, whence int) (int64, error) {
+	if f.closed {
+		return 0, fs.ErrClosed
+	}
+
+	var abs int64
+	switch whence {
+	case io.SeekStart:
+		abs = offset
+	case io.SeekCurrent:
+		abs = f.offset + offset
+	case io.SeekEnd:
+		abs = f.file.Bytes + offset
+	default:
+		return 0, fmt.Errorf("seek: invalid whence %d", whence)
+	}
+	if abs < 0 {
+		return 0, fmt.Errorf("seek: negative position")
+	}
+
+	if abs != f.offset && f.body != nil {
+		_ = f.body.Close()
+		f.body = nil
+	}
+	f.offset = abs
+	return abs, nil
+}
+
+func (f *remoteFile) Close() error {
+	if f.closed {
+		return fs.ErrClosed
+	}
+	f.closed = true
+	if f.body != nil {
+		return f.body.Close()
+	}
+	return nil
+}
+
+// openContent streams a file's content starting at offset. total is the
+// file's size as reported by the response, or -1 if it is unknown. Like
+// fetchRange, the body is not bounded by the client timeout; it is aborted
+// when no data arrives for that long.
+func (rfs *RemoteFileSystem) openContent(fileID string, offset int64) (body io.ReadCloser, total int64, err error) {
+	ctx, cancel := context.WithCancel(rfs.ctx)
+	idle := time.AfterFunc(rfs.client.timeout, cancel)
+	defer func() {
+		if err != nil {
+			idle.Stop()
+			cancel()
+		}
+	}()
+
+	req, err := rfs.client.newRequest(ctx, "GET", "/files/"+fileID+"/content", nil)
+	if err != nil {
+		return nil, -1, err
+	}
+	if offset > 0 {
+		req.Header.Set("Range", "bytes="+strconv.FormatInt(offset, 10)+"-")
+	}
+
+	resp, err := rfs.client.sendStreaming(req)
+	if err != nil {
+		return nil, -1, err
+	}
+	body = &idleBody{
+		idleReader: idleReader{r: resp.Body, timer: idle, timeout: rfs.client.timeout},
+		body:       resp.Body,
+		stop:       func() { idle.Stop(); cancel() },
+	}
+
+	switch resp.StatusCode {
+	case http.StatusPartialContent:
+		total = -1
+		if _, size, ok := strings.Cut(resp.Header.Get("Content-Range"), "/"); ok {
+			if n, err := strconv.ParseInt(size, 10, 64); err == nil {
+				total = n
+			}
+		}
+		return body, total, nil
+	case http.StatusOK:
+		// The server ignored the range; skip to the offset.
+		if _, err := io.CopyN(io.Discard, body, offset); err != nil {
+			_ = resp.Body.Close()
+			return nil, -1, err
+		}
+		return body, resp.ContentLength, nil
+	default:
+		defer closeResponse(resp)
+		if err := checkResponse(resp, "download file"); err != nil {
+			return nil, -1, err
+		}
+		return nil, -1, fmt.Errorf("failed to download file: unexpected status %d", resp.StatusCode)
+	}
+}
+
+// idleBody is a response body read through an idleReader. Closing it also
+// releases the request's idle timer and context.
+type idleBody struct {
+	idleReader
+	body io.Closer
+	stop func()
+}
+
+func (b *idleBody) Close() error {
+	defer b.stop()
+	return b.body.Close()
+}
@@ -0,0 +1,142 @@
+package taskforceai
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func TestRemoteFS(t *testing.T) {
+	created := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	files := []File{
+		{ID: "f1", Filename: "notes.txt", Purpose: "assistants", CreatedAt: created},
+		{ID: "f2", Filename: "docs/guide.md", Purpose: "assistants", CreatedAt: created},
+		{ID: "f3", Filename: "docs/api/ref.md", Purpose: "fine-tune", CreatedAt: created},
+	}
+	content := map[string]string{
+		"f1": "hello notes",
+		"f2": "# Guide",
+		"f3": "reference material",
+	}
+	for i := range files {
+		files[i].Bytes = int64(len(content[files[i].ID]))
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/files":
+			_ = json.NewEncoder(w).Encode(FileListResponse{Files: files, Total: len(files)})
+		case strings.HasSuffix(r.URL.Path, "/content"):
+			id := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/files/"), "/content")
+			http.ServeContent(w, r, id, created, bytes.NewReader([]byte(content[id])))
+		default:
+			id := strings.TrimPrefix(r.URL.Path, "/files/")
+			for _, f := range files {
+				if f.ID == id {
+					_ = json.NewEncoder(w).Encode(f)
+					return
+				}
+			}
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(TaskForceAIOptions{BaseURL: server.URL})
+
+	fsys := RemoteFS(client, nil)
+	if err := fstest.TestFS(fsys, "notes.txt", "docs/guide.md", "docs/api/ref.md"); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := fs.ReadFile(fsys, "docs/api/ref.md")
+	if err != nil || string(data) != "reference material" {
+		t.Errorf("unexpected content %q (err %v)", data, err)
+	}
+
+	f, err := fsys.Open("notes.txt")
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	if _, err := f.(io.Seeker).Seek(6, io.SeekStart); err != nil {
+		t.Fatalf("Seek failed: %v", err)
+	}
+	rest, _ := io.ReadAll(f)
+	if string(rest) != "notes" {
+		t.Errorf("expected ranged read %q, got %q", "notes", rest)
+	}
+	_ = f.Close()
+
+	byPurpose := RemoteFS(client, &RemoteFSOptions{PurposeDirs: true})
+	if err := fstest.TestFS(byPurpose, "assistants/notes.txt", "assistants/docs/guide.md", "fine-tune/docs/api/ref.md"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRemoteFS_StaleSize(t *testing.T) {
+	content := "hello world"
+	var gets int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/files":
+			// The listing predates a change to the file.
+			_ = json.NewEncoder(w).Encode(FileListResponse{Files: []File{{ID: "f1", Filename: "a.txt", Bytes: 5}}})
+		case strings.HasSuffix(r.URL.Path, "/content"):
+			http.ServeContent(w, r, "a.txt", time.Time{}, strings.NewReader(content))
+		default:
+			atomic.AddInt32(&gets, 1)
+			_ = json.NewEncoder(w).Encode(File{ID: "f1", Filename: "a.txt", Bytes: int64(len(content))})
+		}
+	}))
+	defer server.Close()
+	fsys := RemoteFS(NewClient(TaskForceAIOptions{BaseURL: server.URL}), nil)
+
+	f, err := fsys.Open("a.txt")
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer func() { _ = f.Close() }()
+	if gets != 0 {
+		t.Errorf("expected Open to use the listing, got %d metadata requests", gets)
+	}
+
+	data, err := io.ReadAll(f)
+	if err != nil || string(data) != content {
+		t.Errorf("unexpected content %q (err %v)", data, err)
+	}
+	info, _ := f.Stat()
+	if info.Size() != int64(len(content)) || gets != 1 {
+		t.Errorf("expected refreshed size %d after one request, got %d after %d", len(content), info.Size(), gets)
+	}
+}
+
+func TestRemoteFS_SlowBody(t *testing.T) {
+	content := []byte(strings.Repeat("x", 500))
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/files" {
+			_ = json.NewEncoder(w).Encode(FileListResponse{Files: []File{{ID: "f1", Filename: "a.bin", Bytes: int64(len(content))}}})
+			return
+		}
+		for i := 0; i < len(content); i += 100 {
+			_, _ = w.Write(content[i : i+100])
+			w.(http.Flusher).Flush()
+			time.Sleep(40 * time.Millisecond)
+		}
+	}))
+	defer server.Close()
+
+	// The body takes longer than Timeout in total but never stalls for that long.
+	fsys := RemoteFS(NewClient(TaskForceAIOptions{BaseURL: server.URL, Timeout: 100 * time.Millisecond}), nil)
+	data, err := fs.ReadFile(fsys, "a.bin")
+	if err != nil || !bytes.Equal(data, content) {
+		t.Errorf("unexpected content of %d bytes (err %v)", len(data), err)
+	}
+}
@@ -0,0 +1,168 @@
+package taskforceai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// DefaultMaxResponseBytes is the default limit on the size of a JSON
+// response body.
+const DefaultMaxResponseBytes = 32 << 20
+
+const (
+	// maxErrorBodyBytes limits how much of an error response is read.
+	maxErrorBodyBytes = 64 << 10
+	// maxDrainBytes limits how much of an unread body is discarded so the
+	// connection can be reused; larger remainders close the connection.
+	maxDrainBytes = 256 << 10
+)
+
+// APIError is returned when the API responds with a non-2xx status.
+type APIError struct {
+	Op         string // what failed, e.g. "get thread"
+	StatusCode int
+	Code       string // error code from the response body, if any
+	Message    string // error message from the response body, if any
+}
+
+func (e *APIError) Error() string {
+	msg := fmt.Sprintf("failed to %s: status %d", e.Op, e.StatusCode)
+	if e.Message != "" {
+		msg += ": " + e.Message
+	}
+	return msg
+}
+
+// do sends a JSON request and decodes a 2xx response into T. A 204 No
+// Content yields the zero T; any other empty body is an error. Other
+// statuses return an *APIError describing op. The response body is always
+// drained and closed.
+func do[T any](ctx context.Context, c *Client, method, path string, body interface{}, op string) (T, error) {
+	var result T
+
+	resp, err := c.doRequest(ctx, method, path, body)
+	if err != nil {
+		return result, err
+	}
+	defer closeResponse(resp)
+
+	if err := checkResponse(resp, op); err != nil {
+		return result, err
+	}
+
+	err = c.decodeResponse(resp, &result)
+	return result, err
+}
+
+// doNoContent is do for endpoints whose response body is ignored.
+func doNoContent(ctx context.Context, c *Client, method, path string, body interface{}, op string) error {
+	resp, err := c.doRequest(ctx, method, path, body)
+	if err != nil {
+		return err
+	}
+	defer closeResponse(resp)
+
+	return checkResponse(resp, op)
+}
+
+// checkResponse returns an *APIError for a non-2xx response, with the code
+// and message from the error body when it has them.
+func checkResponse(resp *http.Response, op string) error {
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+
+	apiErr := &APIError{Op: op, StatusCode: resp.StatusCode}
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodyBytes))
+	apiErr.Code, apiErr.Message = parseErrorBody(data, resp.Header.Get("Content-Type"))
+	return apiErr
+}
+
+// parseErrorBody extracts an error code and message from the common shapes
+// {"error": "..."}, {"error": {"code": ..., "message": ...}} and
+// {"code": ..., "message": ...}, or from a short plain-text body.
+func parseErrorBody(data []byte, contentType string) (code, message string) {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return "", ""
+	}
+
+	var body struct {
+		Error   json.RawMessage `json:"error"`
+		Code    string          `json:"code"`
+		Message string          `json:"message"`
+	}
+	if json.Unmarshal(data, &body) == nil {
+		code, message = body.Code, body.Message
+		var text string
+		var nested struct {
+			Code    string `json:"code"`
+			Message string `json:"message"`
+		}
+		if json.Unmarshal(body.Error, &text) == nil && text != "" {
+			message = text
+		} else if json.Unmarshal(body.Error, &nested) == nil && nested.Message != "" {
+			code, message = nested.Code, nested.Message
+		}
+		return code, message
+	}
+
+	if strings.HasPrefix(contentType, "text/plain") && len(data) <= 512 {
+		return "", string(data)
+	}
+	return "", ""
+}
+
+// decodeResponse decodes a JSON response body of at most the client's size
+// limit into v. A 204 No Content leaves v unchanged; an empty body with any
+// other status is an error, so callers never mistake it for a result.
+func (c *Client) decodeResponse(resp *http.Response, v interface{}) error {
+	if resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+
+	limit := c.maxResponseBytes
+	if limit <= 0 {
+		limit = DefaultMaxResponseBytes
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, limit+1))
+	if err != nil {
+		return err
+	}
+	if int64(len(data)) > limit {
+		return fmt.Errorf("response body exceeds %d bytes", limit)
+	}
+	if len(bytes.TrimSpace(data)) == 0 {
+		return fmt.Errorf("empty response body (status %d)", resp.StatusCode)
+	}
+
+	return json.Unmarshal(data, v)
+}
+
+// closeResponse drains a bounded remainder of the body so the connection can
+// be reused for keep-alive, then closes it.
+func closeResponse(resp *http.Response) {
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxDrainBytes))
+	_ = resp.Body.Close()
+}
+
+// isStatus reports whether err is an *APIError with one of the given
+// statuses.
+func isStatus(err error, statuses ...int) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	for _, status := range statuses {
+		if apiErr.StatusCode == status {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,99 @@
+package taskforceai
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestDo_StatusAndBodies(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/created":
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"id": 1, "title": "new"}`))
+		case "/empty":
+			w.WriteHeader(http.StatusNoContent)
+		case "/empty-ok":
+			w.WriteHeader(http.StatusOK)
+		case "/error-object":
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusConflict)
+			_, _ = w.Write([]byte(`{"error": {"code": "title_taken", "message": "title already exists"}}`))
+		case "/error-string":
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error": "prompt too long"}`))
+		case "/large":
+			_, _ = w.Write([]byte(`{"title": "` + strings.Repeat("x", 100) + `"}`))
+		}
+	}))
+	defer server.Close()
+	client := NewClient(TaskForceAIOptions{BaseURL: server.URL, MaxResponseBytes: 64})
+	ctx := context.Background()
+
+	thread, err := do[Thread](ctx, client, "GET", "/created", nil, "get thread")
+	if err != nil || thread.ID != 1 {
+		t.Errorf("expected 201 to succeed, got %+v, %v", thread, err)
+	}
+
+	thread, err = do[Thread](ctx, client, "GET", "/empty", nil, "get thread")
+	if err != nil || thread.ID != 0 {
+		t.Errorf("expected empty 204 to decode as zero value, got %+v, %v", thread, err)
+	}
+
+	if _, err := do[TaskStatus](ctx, client, "GET", "/empty-ok", nil, "get task status"); err == nil || !strings.Contains(err.Error(), "empty response body") {
+		t.Errorf("expected error for empty 200 body, got %v", err)
+	}
+	if err := doNoContent(ctx, client, "POST", "/empty-ok", nil, "cancel task"); err != nil {
+		t.Errorf("expected empty 200 body to be ignored by doNoContent, got %v", err)
+	}
+
+	_, err = do[Thread](ctx, client, "PATCH", "/error-object", nil, "update thread")
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusConflict || apiErr.Code != "title_taken" ||
+		err.Error() != "failed to update thread: status 409: title already exists" {
+		t.Errorf("unexpected error %v", err)
+	}
+
+	err = doNoContent(ctx, client, "POST", "/error-string", nil, "run task")
+	if !isStatus(err, http.StatusBadRequest) || !strings.HasSuffix(err.Error(), ": prompt too long") {
+		t.Errorf("unexpected error %v", err)
+	}
+
+	if _, err := do[Thread](ctx, client, "GET", "/large", nil, "get thread"); err == nil || !strings.Contains(err.Error(), "exceeds 64 bytes") {
+		t.Errorf("expected size limit error, got %v", err)
+	}
+}
+
+func TestDo_ReusesConnections(t *testing.T) {
+	var conns atomic.Int32
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(strings.Repeat("not found ", 1000)))
+			return
+		}
+		_, _ = w.Write([]byte(`{"id": 1} ` + strings.Repeat(" ", 1000)))
+	}))
+	server.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			conns.Add(1)
+		}
+	}
+	server.Start()
+	defer server.Close()
+	client := NewClient(TaskForceAIOptions{BaseURL: server.URL})
+
+	for i := 0; i < 5; i++ {
+		_, _ = client.GetThread(context.Background(), 1)
+		_, _ = do[Thread](context.Background(), client, "GET", "/missing", nil, "get thread")
+	}
+	if n := conns.Load(); n != 1 {
+		t.Errorf("expected one reused connection, got %d", n)
+	}
+}
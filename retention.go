@@ -0,0 +1,213 @@
+package taskforceai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// DefaultRetentionDeleteInterval is the default minimum time between deletions.
+const DefaultRetentionDeleteInterval = 100 * time.Millisecond
+
+// RetentionPolicy describes which uploaded files to delete.
+type RetentionPolicy struct {
+	// MaxAge maps a purpose to the maximum age of its files. The "" key
+	// applies to purposes without their own entry.
+	MaxAge map[string]time.Duration
+	// MaxTotalBytes, if positive, deletes the oldest files until the total
+	// size of all files is at or below the limit.
+	MaxTotalBytes int64
+	// KeepNewest protects the newest N files of every purpose.
+	KeepNewest int
+	// ExcludeMetadata protects files whose metadata has any of these
+	// key/value pairs. An empty value protects any file with the key.
+	ExcludeMetadata map[string]string
+}
+
+// RetentionOptions contains options for ApplyRetention and RunRetention.
+type RetentionOptions struct {
+	// DryRun reports what would be deleted without deleting anything.
+	DryRun bool
+	// DeleteInterval is the minimum time between deletions (default: 100ms).
+	DeleteInterval time.Duration
+}
+
+// RetentionReason explains why a file was selected for deletion.
+type RetentionReason string
+
+const (
+	RetentionReasonMaxAge        RetentionReason = "max_age"
+	RetentionReasonMaxTotalBytes RetentionReason = "max_total_bytes"
+)
+
+// RetentionDeletion records one file selected for deletion.
+type RetentionDeletion struct {
+	File   File            `json:"file"`
+	Reason RetentionReason `json:"reason"`
+	Error  string          `json:"error,omitempty"`
+}
+
+// RetentionReport summarizes a retention run.
+type RetentionReport struct {
+	DryRun     bool                `json:"dry_run"`
+	Scanned    int                 `json:"scanned"`
+	Protected  int                 `json:"protected"`
+	Deleted    []RetentionDeletion `json:"deleted"`
+	BytesFreed int64               `json:"bytes_freed"`
+	TotalBytes int64               `json:"total_bytes"` // after the run
+}
+
+// ApplyRetention lists every file, selects the ones policy allows to be
+// deleted and deletes them oldest first, at most one per DeleteInterval.
+// Failed deletions are recorded in the report and returned together.
+func (c *Client) ApplyRetention(ctx context.Context, policy RetentionPolicy, opts *RetentionOptions) (*RetentionReport, error) {
+	o := RetentionOptions{}
+	if opts != nil {
+		o = *opts
+	}
+	if o.DeleteInterval <= 0 {
+		o.DeleteInterval = DefaultRetentionDeleteInterval
+	}
+
+	files, err := c.listAllFiles(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &RetentionReport{DryRun: o.DryRun, Scanned: len(files)}
+	selected := selectForRetention(files, policy, time.Now(), report)
+
+	var errs []error
+	var last time.Time
+	for i := range selected {
+		d := &selected[i]
+		if !o.DryRun {
+			if wait := o.DeleteInterval - time.Since(last); !last.IsZero() && wait > 0 {
+				select {
+				case <-ctx.Done():
+					// Report only the deletions that were attempted.
+					report.Deleted = selected[:i]
+					return report.finish(files), errors.Join(append(errs, ctx.Err())...)
+				case <-time.After(wait):
+				}
+			}
+			last = time.Now()
+			if err := c.DeleteFile(ctx, d.File.ID); err != nil {
+				d.Error = err.Error()
+				errs = append(errs, fmt.Errorf("%s: %w", d.File.ID, err))
+			}
+		}
+	}
+	report.Deleted = selected
+
+	return report.finish(files), errors.Join(errs...)
+}
+
+// RunRetention applies policy immediately and then every interval until ctx
+// is cancelled, passing each result to onReport (which may be nil).
+func (c *Client) RunRetention(ctx context.Context, interval time.Duration, policy RetentionPolicy, opts *RetentionOptions, onReport func(*RetentionReport, error)) error {
+	if interval <= 0 {
+		return fmt.Errorf("interval must be positive")
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		report, err := c.ApplyRetention(ctx, policy, opts)
+		if onReport != nil {
+			onReport(report, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// finish computes the byte totals from the deletion results.
+func (r *RetentionReport) finish(files []File) *RetentionReport {
+	r.TotalBytes, r.BytesFreed = 0, 0
+	for _, f := range files {
+		r.TotalBytes += f.Bytes
+	}
+	for _, d := range r.Deleted {
+		if r.DryRun || d.Error == "" {
+			r.BytesFreed += d.File.Bytes
+		}
+	}
+	r.TotalBytes -= r.BytesFreed
+	return r
+}
+
+// selectForRetention returns the files to delete, oldest first.
+func selectForRetention(files []File, policy RetentionPolicy, now time.Time, report *RetentionReport) []RetentionDeletion {
+	sorted := make([]File, len(files))
+	copy(sorted, files)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].CreatedAt.Before(sorted[j].CreatedAt) })
+
+	protected := make(map[string]bool)
+	perPurpose := map[string]int{}
+	for i := len(sorted) - 1; i >= 0; i-- {
+		f := sorted[i]
+		if policy.KeepNewest > 0 && perPurpose[f.Purpose] < policy.KeepNewest {
+			protected[f.ID] = true
+		}
+		perPurpose[f.Purpose]++
+		if hasProtectedMetadata(f, policy.ExcludeMetadata) {
+			protected[f.ID] = true
+		}
+	}
+	report.Protected = len(protected)
+
+	var total int64
+	for _, f := range sorted {
+		total += f.Bytes
+	}
+
+	var selected []RetentionDeletion
+	chosen := map[string]bool{}
+	for _, f := range sorted {
+		if protected[f.ID] {
+			continue
+		}
+		maxAge, ok := policy.MaxAge[f.Purpose]
+		if !ok {
+			maxAge, ok = policy.MaxAge[""]
+		}
+		if ok && maxAge > 0 && now.Sub(f.CreatedAt) > maxAge {
+			selected = append(selected, RetentionDeletion{File: f, Reason: RetentionReasonMaxAge})
+			chosen[f.ID] = true
+			total -= f.Bytes
+		}
+	}
+
+	if policy.MaxTotalBytes > 0 {
+		for _, f := range sorted {
+			if total <= policy.MaxTotalBytes {
+				break
+			}
+			if protected[f.ID] || chosen[f.ID] {
+				continue
+			}
+			selected = append(selected, RetentionDeletion{File: f, Reason: RetentionReasonMaxTotalBytes})
+			total -= f.Bytes
+		}
+	}
+
+	sort.SliceStable(selected, func(i, j int) bool { return selected[i].File.CreatedAt.Before(selected[j].File.CreatedAt) })
+	return selected
+}
+
+func hasProtectedMetadata(f File, exclude map[string]string) bool {
+	for key, want := range exclude {
+		if got, ok := f.Metadata[key]; ok && (want == "" || got == want) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,151 @@
+package taskforceai
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSelectForRetention(t *testing.T) {
+	now := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+	files := []File{
+		{ID: "old-a", Purpose: "assistants", Bytes: 100, CreatedAt: now.Add(-40 * day)},
+		{ID: "old-pinned", Purpose: "assistants", Bytes: 100, CreatedAt: now.Add(-50 * day), Metadata: map[string]string{"retain": "true"}},
+		{ID: "mid-a", Purpose: "assistants", Bytes: 300, CreatedAt: now.Add(-5 * day)},
+		{ID: "new-a", Purpose: "assistants", Bytes: 300, CreatedAt: now.Add(-1 * day)},
+		{ID: "old-ft", Purpose: "fine-tune", Bytes: 50, CreatedAt: now.Add(-40 * day)},
+	}
+	policy := RetentionPolicy{
+		MaxAge:          map[string]time.Duration{"assistants": 30 * day},
+		MaxTotalBytes:   400,
+		KeepNewest:      1,
+		ExcludeMetadata: map[string]string{"retain": ""},
+	}
+
+	report := &RetentionReport{}
+	selected := selectForRetention(files, policy, now, report)
+
+	var ids []string
+	for _, d := range selected {
+		ids = append(ids, d.File.ID+":"+string(d.Reason))
+	}
+	// old-a expires by age; mid-a goes to get under the byte limit; old-ft is
+	// the newest fine-tune file, new-a the newest assistants file, and
+	// old-pinned is protected by metadata.
+	want := []string{"old-a:max_age", "mid-a:max_total_bytes"}
+	if len(ids) != len(want) || ids[0] != want[0] || ids[1] != want[1] {
+		t.Errorf("expected %v, got %v", want, ids)
+	}
+	if report.Protected != 3 {
+		t.Errorf("expected 3 protected files, got %d", report.Protected)
+	}
+}
+
+// newRetentionServer lists files and records deletions; deleting "file-b"
+// fails with a server error.
+func newRetentionServer(t *testing.T, files []File) (*httptest.Server, func() []time.Time) {
+	t.Helper()
+	var mu sync.Mutex
+	var deletes []time.Time
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/files":
+			_ = json.NewEncoder(w).Encode(FileListResponse{Files: files, Total: len(files)})
+		case r.Method == "DELETE" && strings.HasPrefix(r.URL.Path, "/files/"):
+			mu.Lock()
+			deletes = append(deletes, time.Now())
+			mu.Unlock()
+			if r.URL.Path == "/files/file-b" {
+				http.Error(w, `{"error": "storage unavailable"}`, http.StatusInternalServerError)
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	return server, func() []time.Time {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]time.Time(nil), deletes...)
+	}
+}
+
+func TestClient_ApplyRetention(t *testing.T) {
+	now := time.Now()
+	day := 24 * time.Hour
+	files := []File{
+		{ID: "file-a", Bytes: 100, CreatedAt: now.Add(-50 * day)},
+		{ID: "file-b", Bytes: 200, CreatedAt: now.Add(-40 * day)},
+		{ID: "file-c", Bytes: 50, CreatedAt: now.Add(-1 * day)},
+	}
+	server, deletes := newRetentionServer(t, files)
+	defer server.Close()
+	client := NewClient(TaskForceAIOptions{BaseURL: server.URL})
+	policy := RetentionPolicy{MaxAge: map[string]time.Duration{"": 30 * day}}
+
+	// A dry run reports both expired files without deleting anything.
+	report, err := client.ApplyRetention(context.Background(), policy, &RetentionOptions{DryRun: true})
+	if err != nil {
+		t.Fatalf("dry run failed: %v", err)
+	}
+	if len(deletes()) != 0 {
+		t.Errorf("dry run sent %d deletes", len(deletes()))
+	}
+	if len(report.Deleted) != 2 || report.BytesFreed != 300 || report.TotalBytes != 50 || report.Scanned != 3 {
+		t.Errorf("unexpected dry run report %+v", report)
+	}
+
+	interval := 50 * time.Millisecond
+	report, err = client.ApplyRetention(context.Background(), policy, &RetentionOptions{DeleteInterval: interval})
+	if !isStatus(err, http.StatusInternalServerError) || !strings.Contains(err.Error(), "file-b") {
+		t.Errorf("expected the failed deletion to be returned, got %v", err)
+	}
+	if len(report.Deleted) != 2 || report.Deleted[0].Error != "" || !strings.Contains(report.Deleted[1].Error, "storage unavailable") {
+		t.Errorf("unexpected deletions %+v", report.Deleted)
+	}
+	if report.BytesFreed != 100 || report.TotalBytes != 250 {
+		t.Errorf("expected 100 bytes freed and 250 left, got %d and %d", report.BytesFreed, report.TotalBytes)
+	}
+	if d := deletes(); len(d) != 2 || d[1].Sub(d[0]) < interval {
+		t.Errorf("expected two deletes at least %v apart, got %v", interval, d)
+	}
+}
+
+func TestClient_RunRetention(t *testing.T) {
+	files := []File{{ID: "file-a", Bytes: 100, CreatedAt: time.Now().Add(-time.Hour)}}
+	server, deletes := newRetentionServer(t, files)
+	defer server.Close()
+	client := NewClient(TaskForceAIOptions{BaseURL: server.URL})
+	policy := RetentionPolicy{MaxAge: map[string]time.Duration{"": time.Minute}}
+
+	if err := client.RunRetention(context.Background(), 0, policy, nil, nil); err == nil {
+		t.Error("expected error for zero interval")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var reports []*RetentionReport
+	err := client.RunRetention(ctx, 10*time.Millisecond, policy, &RetentionOptions{DryRun: true}, func(r *RetentionReport, err error) {
+		if err != nil {
+			t.Errorf("run failed: %v", err)
+		}
+		reports = append(reports, r)
+		if len(reports) == 3 {
+			cancel()
+		}
+	})
+	if err != context.Canceled {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+	if len(reports) != 3 || len(reports[2].Deleted) != 1 || len(deletes()) != 0 {
+		t.Errorf("unexpected reports %+v with %d deletes", reports, len(deletes()))
+	}
+}
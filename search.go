@@ -0,0 +1,348 @@
+package taskforceai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+)
+
+// DefaultSearchLimit is the default maximum number of search results.
+const DefaultSearchLimit = 20
+
+// maxSnippetsPerThread limits the message matches returned per thread.
+const maxSnippetsPerThread = 3
+
+// SearchThreadsOptions contains options for SearchThreads.
+type SearchThreadsOptions struct {
+	// Limit is the maximum number of threads returned (default: 20).
+	Limit int
+	// Index is synced and searched when the server has no search endpoint.
+	// Reusing an index across searches avoids refetching unchanged threads;
+	// if nil, a temporary index is built.
+	Index *ThreadIndex
+}
+
+// ThreadSearchResult is a thread matching a search, with the best matching
+// messages.
+type ThreadSearchResult struct {
+	Thread  Thread         `json:"thread"`
+	Score   float64        `json:"score"`
+	Matches []MessageMatch `json:"matches,omitempty"`
+}
+
+// MessageMatch is a message matching a search.
+type MessageMatch struct {
+	MessageID int    `json:"message_id"`
+	Role      string `json:"role"`
+	Snippet   string `json:"snippet"`
+}
+
+// ThreadSearchResponse contains search results from the server.
+type ThreadSearchResponse struct {
+	Results []ThreadSearchResult `json:"results"`
+}
+
+// SearchThreads finds threads whose title or messages match query, best
+// matches first. The server's search endpoint is used if available;
+// otherwise the threads are indexed and searched locally.
+func (c *Client) SearchThreads(ctx context.Context, query string, opts *SearchThreadsOptions) ([]ThreadSearchResult, error) {
+	if strings.TrimSpace(query) == "" {
+		return nil, fmt.Errorf("query is required")
+	}
+	o := SearchThreadsOptions{}
+	if opts != nil {
+		o = *opts
+	}
+	if o.Limit <= 0 {
+		o.Limit = DefaultSearchLimit
+	}
+
+	results, err := c.searchThreadsOnServer(ctx, query, o.Limit)
+	if err != nil || results != nil {
+		return results, err
+	}
+
+	index := o.Index
+	if index == nil {
+		index = NewThreadIndex()
+	}
+	if err := index.Sync(ctx, c); err != nil {
+		return nil, err
+	}
+	return index.Search(query, o.Limit), nil
+}
+
+// searchThreadsOnServer returns nil if the server has no search endpoint.
+func (c *Client) searchThreadsOnServer(ctx context.Context, query string, limit int) ([]ThreadSearchResult, error) {
+	path := "/threads/search?" + url.Values{"q": {query}, "limit": {strconv.Itoa(limit)}}.Encode()
+
+	result, err := do[ThreadSearchResponse](ctx, c, "GET", path, nil, "search threads")
+	if isStatus(err, http.StatusNotFound, http.StatusMethodNotAllowed, http.StatusNotImplemented) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if result.Results == nil {
+		result.Results = []ThreadSearchResult{}
+	}
+
+	return result.Results, nil
+}
+
+// ThreadIndex is a local full-text index of threads and their messages. It
+// is safe for concurrent use.
+type ThreadIndex struct {
+	mu      sync.RWMutex
+	threads map[int]*indexedThread
+}
+
+type indexedThread struct {
+	Thread   Thread          `json:"thread"`
+	Messages []ThreadMessage `json:"messages"`
+}
+
+// NewThreadIndex returns an empty index.
+func NewThreadIndex() *ThreadIndex {
+	return &ThreadIndex{threads: map[int]*indexedThread{}}
+}
+
+// LoadThreadIndex reads an index saved with Save.
+func LoadThreadIndex(path string) (*ThreadIndex, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var threads []*indexedThread
+	if err := json.Unmarshal(data, &threads); err != nil {
+		return nil, err
+	}
+	idx := NewThreadIndex()
+	for _, t := range threads {
+		idx.threads[t.Thread.ID] = t
+	}
+	return idx, nil
+}
+
+// Save writes the index to path.
+func (idx *ThreadIndex) Save(path string) error {
+	idx.mu.RLock()
+	threads := make([]*indexedThread, 0, len(idx.threads))
+	for _, t := range idx.threads {
+		threads = append(threads, t)
+	}
+	idx.mu.RUnlock()
+	sort.Slice(threads, func(i, j int) bool { return threads[i].Thread.ID < threads[j].Thread.ID })
+
+	data, err := json.Marshal(threads)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(path, data)
+}
+
+// Sync brings the index up to date: messages are fetched for new threads
+// and threads whose UpdatedAt changed, and deleted threads are dropped.
+func (idx *ThreadIndex) Sync(ctx context.Context, client *Client) error {
+	threads, err := client.listAllThreads(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	seen := make(map[int]bool, len(threads))
+	for _, thread := range threads {
+		seen[thread.ID] = true
+
+		idx.mu.RLock()
+		cached := idx.threads[thread.ID]
+		idx.mu.RUnlock()
+		if cached != nil && cached.Thread.UpdatedAt.Equal(thread.UpdatedAt) {
+			continue
+		}
+
+		messages, err := client.listAllThreadMessages(ctx, thread.ID)
+		if err != nil {
+			return fmt.Errorf("failed to index thread %d: %w", thread.ID, err)
+		}
+
+		idx.mu.Lock()
+		idx.threads[thread.ID] = &indexedThread{Thread: thread, Messages: messages}
+		idx.mu.Unlock()
+	}
+
+	idx.mu.Lock()
+	for id := range idx.threads {
+		if !seen[id] {
+			delete(idx.threads, id)
+		}
+	}
+	idx.mu.Unlock()
+
+	return nil
+}
+
+// Search ranks the indexed threads against query. Terms are weighted by
+// rarity across threads; title matches count triple and threads containing
+// the whole query as a phrase rank higher.
+func (idx *ThreadIndex) Search(query string, limit int) []ThreadSearchResult {
+	terms := searchTerms(query)
+	if len(terms) == 0 {
+		return []ThreadSearchResult{}
+	}
+	if limit <= 0 {
+		limit = DefaultSearchLimit
+	}
+	phrase := strings.ToLower(strings.TrimSpace(query))
+
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	// Document frequency of each term, for weighting.
+	df := map[string]int{}
+	for _, t := range idx.threads {
+		for _, term := range terms {
+			if t.contains(term) {
+				df[term]++
+			}
+		}
+	}
+
+	weight := func(term string) float64 {
+		return math.Log(1 + float64(len(idx.threads))/float64(df[term]))
+	}
+
+	results := []ThreadSearchResult{}
+	for _, t := range idx.threads {
+		var score float64
+
+		title := strings.ToLower(t.Thread.Title)
+		for _, term := range terms {
+			if n := strings.Count(title, term); n > 0 {
+				score += 3 * float64(n) * weight(term)
+			}
+		}
+		if strings.Contains(title, phrase) {
+			score += 3
+		}
+
+		type scored struct {
+			match MessageMatch
+			score float64
+		}
+		var matches []scored
+		for _, m := range t.Messages {
+			content := strings.ToLower(m.Content)
+			var s float64
+			for _, term := range terms {
+				if n := strings.Count(content, term); n > 0 {
+					s += float64(n) * weight(term)
+				}
+			}
+			if s == 0 {
+				continue
+			}
+			if len(terms) > 1 && strings.Contains(content, phrase) {
+				s += 2
+			}
+			score += s
+			matches = append(matches, scored{MessageMatch{MessageID: m.ID, Role: m.Role, Snippet: snippet(m.Content, content, terms)}, s})
+		}
+		if score == 0 {
+			continue
+		}
+
+		sort.SliceStable(matches, func(i, j int) bool { return matches[i].score > matches[j].score })
+		result := ThreadSearchResult{Thread: t.Thread, Score: score}
+		for i := 0; i < len(matches) && i < maxSnippetsPerThread; i++ {
+			result.Matches = append(result.Matches, matches[i].match)
+		}
+		results = append(results, result)
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].Thread.UpdatedAt.After(results[j].Thread.UpdatedAt)
+	})
+	if len(results) > limit {
+		results = results[:limit]
+	}
+	return results
+}
+
+func (t *indexedThread) contains(term string) bool {
+	if strings.Contains(strings.ToLower(t.Thread.Title), term) {
+		return true
+	}
+	for _, m := range t.Messages {
+		if strings.Contains(strings.ToLower(m.Content), term) {
+			return true
+		}
+	}
+	return false
+}
+
+// searchTerms splits a query into distinct lowercase words.
+func searchTerms(query string) []string {
+	seen := map[string]bool{}
+	var terms []string
+	for _, w := range strings.FieldsFunc(strings.ToLower(query), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if !seen[w] {
+			seen[w] = true
+			terms = append(terms, w)
+		}
+	}
+	return terms
+}
+
+// snippetRadius is the number of bytes of context around a match.
+const snippetRadius = 60
+
+// snippet returns the text around the first matching term. lower is the
+// lowercased content.
+func snippet(content, lower string, terms []string) string {
+	if len(lower) != len(content) {
+		// Lowercasing changed byte offsets; show the lowercased text.
+		content = lower
+	}
+
+	pos := -1
+	for _, term := range terms {
+		if i := strings.Index(lower, term); i >= 0 && (pos < 0 || i < pos) {
+			pos = i
+		}
+	}
+	if pos < 0 {
+		pos = 0
+	}
+
+	start, end := pos-snippetRadius, pos+snippetRadius
+	prefix, suffix := "…", "…"
+	if start <= 0 {
+		start, prefix = 0, ""
+	}
+	if end >= len(content) {
+		end, suffix = len(content), ""
+	}
+	for start > 0 && !utf8.RuneStart(content[start]) {
+		start--
+	}
+	for end < len(content) && !utf8.RuneStart(content[end]) {
+		end++
+	}
+
+	return prefix + strings.Join(strings.Fields(content[start:end]), " ") + suffix
+}
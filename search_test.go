@@ -0,0 +1,83 @@
+package taskforceai
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestClient_SearchThreads_LocalIndex(t *testing.T) {
+	updated := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	threads := []Thread{
+		{ID: 1, Title: "Billing migration", UpdatedAt: updated},
+		{ID: 2, Title: "Lunch plans", UpdatedAt: updated},
+		{ID: 3, Title: "Ops notes", UpdatedAt: updated},
+	}
+	messages := map[string][]ThreadMessage{
+		"/threads/1/messages": {{ID: 10, Role: MessageRoleUser, Content: "How do we move invoices during the billing migration?"}},
+		"/threads/2/messages": {{ID: 20, Role: MessageRoleUser, Content: "Pizza or sushi?"}},
+		"/threads/3/messages": {{ID: 30, Role: MessageRoleAssistant, Content: "The migration of the billing database is scheduled for Friday."}},
+	}
+	var messageFetches atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/threads/search":
+			w.WriteHeader(http.StatusNotFound)
+		case r.URL.Path == "/threads":
+			_ = json.NewEncoder(w).Encode(ThreadListResponse{Threads: threads, Total: len(threads)})
+		case strings.HasSuffix(r.URL.Path, "/messages"):
+			messageFetches.Add(1)
+			_ = json.NewEncoder(w).Encode(ThreadMessagesResponse{Messages: messages[r.URL.Path], Total: 1})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	client := NewClient(TaskForceAIOptions{BaseURL: server.URL})
+
+	index := NewThreadIndex()
+	results, err := client.SearchThreads(context.Background(), "billing migration", &SearchThreadsOptions{Index: index})
+	if err != nil {
+		t.Fatalf("SearchThreads failed: %v", err)
+	}
+	if len(results) != 2 || results[0].Thread.ID != 1 || results[1].Thread.ID != 3 {
+		t.Fatalf("unexpected ranking %+v", results)
+	}
+	if m := results[0].Matches; len(m) != 1 || m[0].MessageID != 10 || !strings.Contains(m[0].Snippet, "billing migration") {
+		t.Errorf("unexpected matches %+v", m)
+	}
+
+	// Unchanged threads are not refetched.
+	if _, err := client.SearchThreads(context.Background(), "sushi", &SearchThreadsOptions{Index: index}); err != nil {
+		t.Fatalf("second search failed: %v", err)
+	}
+	if n := messageFetches.Load(); n != 3 {
+		t.Errorf("expected 3 message fetches, got %d", n)
+	}
+
+	path := filepath.Join(t.TempDir(), "index.json")
+	if err := index.Save(path); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	loaded, err := LoadThreadIndex(path)
+	if err != nil {
+		t.Fatalf("LoadThreadIndex failed: %v", err)
+	}
+	if results := loaded.Search("pizza", 0); len(results) != 1 || results[0].Thread.ID != 2 {
+		t.Errorf("unexpected results from loaded index %+v", results)
+	}
+}
+
+func TestSnippet(t *testing.T) {
+	content := strings.Repeat("a ", 50) + "needle" + strings.Repeat(" b", 50)
+	got := snippet(content, strings.ToLower(content), []string{"needle"})
+	if !strings.HasPrefix(got, "…") || !strings.HasSuffix(got, "…") || !strings.Contains(got, "needle") {
+		t.Errorf("unexpected snippet %q", got)
+	}
+}
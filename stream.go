@@ -4,7 +4,6 @@ import (
 	"bufio"
 	"context"
 	"encoding/json"
-	"fmt"
 	"net/http"
 	"strings"
 )
@@ -20,28 +19,23 @@ type sseStream struct {
 func (c *Client) StreamTaskStatus(ctx context.Context, taskID string) (TaskStatusStream, error) {
 	streamCtx, cancel := context.WithCancel(ctx)
 
-	url := c.baseURL + "/stream/" + taskID
-	req, err := http.NewRequestWithContext(streamCtx, "GET", url, nil)
+	req, err := c.newRequest(streamCtx, "GET", "/stream/"+taskID, nil)
 	if err != nil {
 		cancel()
 		return nil, err
 	}
-
 	req.Header.Set("Accept", "text/event-stream")
-	if c.apiKey != "" {
-		req.Header.Set("Authorization", "Bearer "+c.apiKey)
-	}
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.send(req)
 	if err != nil {
 		cancel()
 		return nil, err
 	}
 
-	if resp.StatusCode != http.StatusOK {
-		_ = resp.Body.Close()
+	if err := checkResponse(resp, "open task stream"); err != nil {
+		closeResponse(resp)
 		cancel()
-		return nil, fmt.Errorf("stream error: status %d", resp.StatusCode)
+		return nil, err
 	}
 
 	return &sseStream{
@@ -0,0 +1,302 @@
+package taskforceai
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// SyncAction describes what a directory sync does with one file.
+type SyncAction string
+
+const (
+	SyncActionUpload    SyncAction = "upload"    // new local file
+	SyncActionUpdate    SyncAction = "update"    // changed local file
+	SyncActionDelete    SyncAction = "delete"    // remote file missing locally or superseded
+	SyncActionUnchanged SyncAction = "unchanged" // identical content
+)
+
+// SyncOptions contains options for SyncDirectory.
+type SyncOptions struct {
+	// RemotePrefix is prepended to local relative paths to form remote
+	// filenames. Only remote files under the prefix are considered.
+	RemotePrefix string
+	// Purpose is used for uploads and, if set, limits the remote files
+	// considered to that purpose.
+	Purpose string
+	// Include, if non-empty, limits the sync to paths matching at least one
+	// pattern. Exclude skips matching paths and directories. Patterns use
+	// path.Match syntax and are matched against both the relative path and
+	// the base name.
+	Include []string
+	Exclude []string
+	// Delete removes remote files that no longer exist locally, and older
+	// files sharing the name of an unchanged file.
+	Delete bool
+	// DryRun computes the changes without uploading or deleting anything.
+	DryRun bool
+}
+
+// SyncChange records the action taken for one path.
+type SyncChange struct {
+	Path   string     `json:"path"`
+	Action SyncAction `json:"action"`
+	FileID string     `json:"file_id,omitempty"` // resulting (or deleted) remote file
+	Bytes  int64      `json:"bytes"`
+	Error  string     `json:"error,omitempty"`
+}
+
+// SyncReport summarizes a directory sync.
+type SyncReport struct {
+	DryRun  bool         `json:"dry_run"`
+	Changes []SyncChange `json:"changes"`
+}
+
+// Count returns the number of changes with the given action.
+func (r *SyncReport) Count(action SyncAction) int {
+	n := 0
+	for _, c := range r.Changes {
+		if c.Action == action {
+			n++
+		}
+	}
+	return n
+}
+
+type localSyncFile struct {
+	path   string // local path
+	rel    string // slash-separated path relative to the root
+	size   int64
+	digest string
+}
+
+// SyncDirectory makes the uploaded files mirror the local directory dir.
+// Local files are matched to remote files by name (RemotePrefix plus the
+// slash-separated relative path) and compared by SHA-256 digest; new and
+// changed files are uploaded with the digest in their metadata, and a
+// replaced remote file is deleted after its successor is uploaded. Older
+// remote files sharing the name of an unchanged file are deleted as well.
+// Failures on individual files are recorded in the report and returned
+// together.
+func (c *Client) SyncDirectory(ctx context.Context, dir string, opts *SyncOptions) (*SyncReport, error) {
+	o := SyncOptions{}
+	if opts != nil {
+		o = *opts
+	}
+	for _, pattern := range append(append([]string{}, o.Include...), o.Exclude...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+		}
+	}
+
+	local, err := scanSyncDir(dir, &o)
+	if err != nil {
+		return nil, err
+	}
+
+	remote, err := c.listSyncRemote(ctx, &o)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &SyncReport{DryRun: o.DryRun}
+	var errs []error
+	seen := map[string]bool{}
+
+	for _, lf := range local {
+		name := o.RemotePrefix + lf.rel
+		seen[name] = true
+
+		existing := remote[name]
+		change := SyncChange{Path: lf.rel, Action: SyncActionUpload, Bytes: lf.size}
+		if len(existing) > 0 {
+			change.Action = SyncActionUpdate
+			if existing[0].SHA256() == lf.digest && existing[0].Bytes == lf.size {
+				change.Action = SyncActionUnchanged
+				change.FileID = existing[0].ID
+				report.Changes = append(report.Changes, change)
+				// Older files of the same name are left over from an update
+				// whose delete step failed.
+				if o.Delete {
+					errs = append(errs, c.deleteSyncFiles(ctx, lf.rel, existing[1:], &o, report)...)
+				}
+				continue
+			}
+		}
+
+		if !o.DryRun {
+			file, err := c.uploadSyncFile(ctx, name, lf, &o)
+			if err != nil {
+				change.Error = err.Error()
+				errs = append(errs, fmt.Errorf("%s: %w", lf.rel, err))
+				report.Changes = append(report.Changes, change)
+				continue
+			}
+			change.FileID = file.ID
+			for _, old := range existing {
+				if err := c.DeleteFile(ctx, old.ID); err != nil {
+					change.Error = err.Error()
+					errs = append(errs, fmt.Errorf("%s: removing replaced file %s: %w", lf.rel, old.ID, err))
+				}
+			}
+		}
+		report.Changes = append(report.Changes, change)
+	}
+
+	if o.Delete {
+		names := make([]string, 0, len(remote))
+		for name := range remote {
+			if !seen[name] {
+				names = append(names, name)
+			}
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			rel := strings.TrimPrefix(name, o.RemotePrefix)
+			if !o.matches(rel) {
+				continue
+			}
+			errs = append(errs, c.deleteSyncFiles(ctx, rel, remote[name], &o, report)...)
+		}
+	}
+
+	return report, errors.Join(errs...)
+}
+
+// deleteSyncFiles deletes remote files stored under rel, unless this is a dry
+// run, and records a delete change for each.
+func (c *Client) deleteSyncFiles(ctx context.Context, rel string, files []File, o *SyncOptions, report *SyncReport) []error {
+	var errs []error
+	for _, file := range files {
+		change := SyncChange{Path: rel, Action: SyncActionDelete, FileID: file.ID, Bytes: file.Bytes}
+		if !o.DryRun {
+			if err := c.DeleteFile(ctx, file.ID); err != nil {
+				change.Error = err.Error()
+				errs = append(errs, fmt.Errorf("%s: %w", rel, err))
+			}
+		}
+		report.Changes = append(report.Changes, change)
+	}
+	return errs
+}
+
+func (c *Client) uploadSyncFile(ctx context.Context, name string, lf localSyncFile, o *SyncOptions) (*File, error) {
+	f, err := os.Open(lf.path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	return c.UploadFile(ctx, name, f, &FileUploadOptions{
+		Purpose:  o.Purpose,
+		Metadata: map[string]string{FileMetadataSHA256: lf.digest},
+	})
+}
+
+// listSyncRemote returns the remote files in scope keyed by filename, newest
+// first within each name.
+func (c *Client) listSyncRemote(ctx context.Context, o *SyncOptions) (map[string][]File, error) {
+	all, err := c.listAllFiles(ctx, &ListFilesOptions{Purpose: o.Purpose, FilenamePrefix: o.RemotePrefix})
+	if err != nil {
+		return nil, err
+	}
+
+	remote := map[string][]File{}
+	for _, file := range all {
+		if !strings.HasPrefix(file.Filename, o.RemotePrefix) {
+			continue
+		}
+		if o.Purpose != "" && file.Purpose != o.Purpose {
+			continue
+		}
+		remote[file.Filename] = append(remote[file.Filename], file)
+	}
+
+	for _, files := range remote {
+		sort.SliceStable(files, func(i, j int) bool { return files[i].CreatedAt.After(files[j].CreatedAt) })
+	}
+	return remote, nil
+}
+
+func scanSyncDir(dir string, o *SyncOptions) ([]localSyncFile, error) {
+	var files []localSyncFile
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+
+		if d.IsDir() {
+			if rel != "." && matchAny(o.Exclude, rel) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() || !o.matches(rel) {
+			return nil
+		}
+
+		size, digest, err := hashFile(p)
+		if err != nil {
+			return err
+		}
+		files = append(files, localSyncFile{path: p, rel: rel, size: size, digest: digest})
+		return nil
+	})
+	return files, err
+}
+
+// matches reports whether rel passes the include and exclude patterns. An
+// exclude pattern matching a parent directory excludes everything below it.
+func (o *SyncOptions) matches(rel string) bool {
+	if len(o.Include) > 0 && !matchAny(o.Include, rel) {
+		return false
+	}
+	for p := rel; p != "."; p = path.Dir(p) {
+		if matchAny(o.Exclude, p) {
+			return false
+		}
+	}
+	return true
+}
+
+func matchAny(patterns []string, rel string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, rel); ok {
+			return true
+		}
+		if ok, _ := path.Match(pattern, path.Base(rel)); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func hashFile(p string) (int64, string, error) {
+	f, err := os.Open(p)
+	if err != nil {
+		return 0, "", err
+	}
+	defer func() { _ = f.Close() }()
+
+	hash := sha256.New()
+	size, err := io.Copy(hash, f)
+	if err != nil {
+		return 0, "", err
+	}
+	return size, hex.EncodeToString(hash.Sum(nil)), nil
+}
@@ -0,0 +1,170 @@
+package taskforceai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeFileStore implements the file list, get, lookup, upload and delete
+// endpoints.
+type fakeFileStore struct {
+	mu     sync.Mutex
+	files  []File
+	nextID int
+}
+
+func (s *fakeFileStore) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch {
+	case r.Method == "GET" && r.URL.Path == "/files":
+		_ = json.NewEncoder(w).Encode(FileListResponse{Files: s.files, Total: len(s.files)})
+	case r.Method == "GET" && r.URL.Path == "/files/lookup":
+		for _, f := range s.files {
+			if f.SHA256() == r.URL.Query().Get("sha256") {
+				_ = json.NewEncoder(w).Encode(f)
+				return
+			}
+		}
+		w.WriteHeader(http.StatusNotFound)
+	case r.Method == "GET" && strings.HasPrefix(r.URL.Path, "/files/"):
+		for _, f := range s.files {
+			if f.ID == strings.TrimPrefix(r.URL.Path, "/files/") {
+				_ = json.NewEncoder(w).Encode(f)
+				return
+			}
+		}
+		w.WriteHeader(http.StatusNotFound)
+	case r.Method == "POST" && r.URL.Path == "/files":
+		// Read parts directly: FormFile strips directories from filenames.
+		reader, err := r.MultipartReader()
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		s.nextID++
+		file := File{ID: fmt.Sprintf("file-%d", s.nextID), CreatedAt: time.Now()}
+		for {
+			part, err := reader.NextPart()
+			if err != nil {
+				break
+			}
+			_, params, _ := mime.ParseMediaType(part.Header.Get("Content-Disposition"))
+			data, _ := io.ReadAll(part)
+			switch part.FormName() {
+			case "file":
+				file.Filename, file.Bytes = params["filename"], int64(len(data))
+			case "purpose":
+				file.Purpose = string(data)
+			case "metadata":
+				_ = json.Unmarshal(data, &file.Metadata)
+			}
+		}
+		s.files = append(s.files, file)
+		_ = json.NewEncoder(w).Encode(file)
+	case r.Method == "DELETE":
+		id := strings.TrimPrefix(r.URL.Path, "/files/")
+		for i, f := range s.files {
+			if f.ID == id {
+				s.files = append(s.files[:i], s.files[i+1:]...)
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+		}
+		w.WriteHeader(http.StatusNotFound)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func TestClient_SyncDirectory(t *testing.T) {
+	store := &fakeFileStore{}
+	server := httptest.NewServer(store)
+	defer server.Close()
+	client := NewClient(TaskForceAIOptions{BaseURL: server.URL})
+
+	dir := t.TempDir()
+	write := func(name, content string) {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		_ = os.MkdirAll(filepath.Dir(p), 0o755)
+		if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("a.md", "alpha")
+	write("sub/b.md", "beta")
+	write("skip.tmp", "ignored")
+
+	opts := &SyncOptions{RemotePrefix: "kb/", Exclude: []string{"*.tmp"}, Delete: true}
+
+	// 1. Initial sync uploads everything not excluded.
+	report, err := client.SyncDirectory(context.Background(), dir, opts)
+	if err != nil {
+		t.Fatalf("initial sync failed: %v", err)
+	}
+	if report.Count(SyncActionUpload) != 2 || len(store.files) != 2 {
+		t.Fatalf("expected 2 uploads, got %+v", report.Changes)
+	}
+
+	// 2. Change one file and remove another.
+	write("a.md", "alpha v2")
+	_ = os.Remove(filepath.Join(dir, "sub", "b.md"))
+	write("c.md", "gamma")
+
+	dry, err := client.SyncDirectory(context.Background(), dir, &SyncOptions{RemotePrefix: "kb/", Exclude: []string{"*.tmp"}, Delete: true, DryRun: true})
+	if err != nil {
+		t.Fatalf("dry run failed: %v", err)
+	}
+	if dry.Count(SyncActionUpdate) != 1 || dry.Count(SyncActionDelete) != 1 || dry.Count(SyncActionUpload) != 1 || len(store.files) != 2 {
+		t.Fatalf("unexpected dry run %+v with %d files", dry.Changes, len(store.files))
+	}
+
+	report, err = client.SyncDirectory(context.Background(), dir, opts)
+	if err != nil {
+		t.Fatalf("second sync failed: %v", err)
+	}
+	names := map[string]bool{}
+	for _, f := range store.files {
+		names[f.Filename] = true
+	}
+	if len(store.files) != 2 || !names["kb/a.md"] || !names["kb/c.md"] {
+		t.Errorf("unexpected remote files after sync: %+v", store.files)
+	}
+
+	// 3. A third sync finds nothing to do.
+	report, err = client.SyncDirectory(context.Background(), dir, opts)
+	if err != nil || report.Count(SyncActionUnchanged) != 2 || len(report.Changes) != 2 {
+		t.Errorf("expected no changes, got %+v (err %v)", report.Changes, err)
+	}
+
+	// 4. An older duplicate of an unchanged file, left by a failed delete,
+	// is kept without Delete and pruned with it.
+	store.mu.Lock()
+	store.files = append(store.files, File{ID: "file-stale", Filename: "kb/a.md", Bytes: 5})
+	store.mu.Unlock()
+	report, err = client.SyncDirectory(context.Background(), dir, &SyncOptions{RemotePrefix: "kb/", Exclude: []string{"*.tmp"}})
+	if err != nil || report.Count(SyncActionDelete) != 0 || len(store.files) != 3 {
+		t.Fatalf("expected stale duplicate to be kept without Delete, got %+v (err %v)", report.Changes, err)
+	}
+	report, err = client.SyncDirectory(context.Background(), dir, opts)
+	if err != nil || report.Count(SyncActionUnchanged) != 2 || report.Count(SyncActionDelete) != 1 {
+		t.Fatalf("expected stale duplicate to be deleted, got %+v (err %v)", report.Changes, err)
+	}
+	for _, f := range store.files {
+		if f.ID == "file-stale" {
+			t.Error("stale duplicate still present")
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package taskforceai
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+)
+
+// ThreadRunEvent is a status update from a thread run stream. Message is set
+// on the final event of a completed run.
+type ThreadRunEvent struct {
+	Status  TaskStatus
+	Message *ThreadMessage
+}
+
+// ThreadRunStream delivers status updates for a thread run. Next returns
+// io.EOF after the final event, or io.ErrUnexpectedEOF if the stream ends
+// before the run completes or fails.
+type ThreadRunStream interface {
+	Next() (ThreadRunEvent, error)
+	Close() error
+	Run() ThreadRunResponse
+}
+
+// RunInThreadAndWait submits a prompt within a thread, waits for the task to
+// complete and returns the assistant message it produced.
+func (c *Client) RunInThreadAndWait(ctx context.Context, threadID int, opts ThreadRunOptions, pollInterval time.Duration, maxAttempts int, callback TaskStatusCallback) (*ThreadMessage, error) {
+	run, err := c.startThreadRun(ctx, threadID, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := c.WaitForCompletion(ctx, run.TaskID, pollInterval, maxAttempts, callback); err != nil {
+		return nil, err
+	}
+
+	return c.runMessage(ctx, run)
+}
+
+// RunInThreadStream submits a prompt within a thread and streams the task's
+// status updates, ending with the assistant message.
+func (c *Client) RunInThreadStream(ctx context.Context, threadID int, opts ThreadRunOptions) (ThreadRunStream, error) {
+	run, err := c.startThreadRun(ctx, threadID, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	stream, err := c.StreamTaskStatus(ctx, run.TaskID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &threadRunStream{client: c, ctx: ctx, run: *run, stream: stream}, nil
+}
+
+func (c *Client) startThreadRun(ctx context.Context, threadID int, opts ThreadRunOptions) (*ThreadRunResponse, error) {
+	run, err := c.RunInThread(ctx, threadID, opts)
+	if err != nil {
+		return nil, err
+	}
+	if run.ThreadID == 0 {
+		run.ThreadID = threadID
+	}
+	return run, nil
+}
+
+// runMessage fetches the assistant message of a finished run.
+func (c *Client) runMessage(ctx context.Context, run *ThreadRunResponse) (*ThreadMessage, error) {
+	if run.MessageID == 0 {
+		return nil, fmt.Errorf("thread run for task %s has no message ID", run.TaskID)
+	}
+	return c.GetThreadMessage(ctx, run.ThreadID, run.MessageID)
+}
+
+type threadRunStream struct {
+	client *Client
+	ctx    context.Context
+	run    ThreadRunResponse
+	stream TaskStatusStream
+	done   bool
+}
+
+func (s *threadRunStream) Run() ThreadRunResponse {
+	return s.run
+}
+
+func (s *threadRunStream) Close() error {
+	return s.stream.Close()
+}
+
+func (s *threadRunStream) Next() (ThreadRunEvent, error) {
+	if s.done {
+		return ThreadRunEvent{}, io.EOF
+	}
+
+	status, err := s.stream.Next()
+	if err == io.EOF {
+		return ThreadRunEvent{}, io.ErrUnexpectedEOF
+	}
+	if err != nil {
+		return ThreadRunEvent{}, err
+	}
+	event := ThreadRunEvent{Status: status}
+
+	switch status.Status {
+	case "completed":
+		s.done = true
+		message, err := s.client.runMessage(s.ctx, &s.run)
+		if err != nil {
+			return event, err
+		}
+		event.Message = message
+	case "failed":
+		s.done = true
+		errMsg := "task failed"
+		if status.Error != nil {
+			errMsg = *status.Error
+		}
+		return event, fmt.Errorf("task failed: %s", errMsg)
+	}
+
+	return event, nil
+}
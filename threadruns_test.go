@@ -0,0 +1,113 @@
+package taskforceai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func newThreadRunServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	polls := 0
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/threads/4/runs":
+			_ = json.NewEncoder(w).Encode(ThreadRunResponse{TaskID: "task-1", ThreadID: 4, MessageID: 12})
+		case "/status/task-1":
+			polls++
+			status := "processing"
+			if polls > 1 {
+				status = "completed"
+			}
+			_ = json.NewEncoder(w).Encode(TaskStatus{TaskID: "task-1", Status: status})
+		case "/stream/task-1":
+			w.Header().Set("Content-Type", "text/event-stream")
+			fmt.Fprint(w, "data: {\"taskId\":\"task-1\",\"status\":\"processing\"}\n\n")
+			fmt.Fprint(w, "data: {\"taskId\":\"task-1\",\"status\":\"completed\"}\n\n")
+		case "/threads/4/messages/12":
+			_ = json.NewEncoder(w).Encode(ThreadMessage{ID: 12, ThreadID: 4, Role: MessageRoleAssistant, Content: "Rome."})
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func TestClient_RunInThreadAndWait(t *testing.T) {
+	server := newThreadRunServer(t)
+	defer server.Close()
+	client := NewClient(TaskForceAIOptions{BaseURL: server.URL})
+
+	message, err := client.RunInThreadAndWait(context.Background(), 4, ThreadRunOptions{Prompt: "capital of Italy?"}, 1, 5, nil)
+	if err != nil {
+		t.Fatalf("RunInThreadAndWait failed: %v", err)
+	}
+	if message.ID != 12 || message.Content != "Rome." {
+		t.Errorf("unexpected message %+v", message)
+	}
+}
+
+func TestClient_RunInThreadStream(t *testing.T) {
+	server := newThreadRunServer(t)
+	defer server.Close()
+	client := NewClient(TaskForceAIOptions{BaseURL: server.URL})
+
+	stream, err := client.RunInThreadStream(context.Background(), 4, ThreadRunOptions{Prompt: "capital of Italy?"})
+	if err != nil {
+		t.Fatalf("RunInThreadStream failed: %v", err)
+	}
+	defer func() { _ = stream.Close() }()
+
+	var events []ThreadRunEvent
+	for {
+		event, err := stream.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Next failed: %v", err)
+		}
+		events = append(events, event)
+	}
+
+	if len(events) != 2 || events[0].Message != nil {
+		t.Fatalf("unexpected events %+v", events)
+	}
+	if last := events[1]; last.Status.Status != "completed" || last.Message == nil || last.Message.Content != "Rome." {
+		t.Errorf("unexpected final event %+v", last)
+	}
+}
+
+func TestClient_RunInThreadStream_Truncated(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/threads/4/runs":
+			_ = json.NewEncoder(w).Encode(ThreadRunResponse{TaskID: "task-1", ThreadID: 4, MessageID: 12})
+		case "/stream/task-1":
+			w.Header().Set("Content-Type", "text/event-stream")
+			fmt.Fprint(w, "data: {\"taskId\":\"task-1\",\"status\":\"processing\"}\n\n")
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	client := NewClient(TaskForceAIOptions{BaseURL: server.URL})
+
+	stream, err := client.RunInThreadStream(context.Background(), 4, ThreadRunOptions{Prompt: "capital of Italy?"})
+	if err != nil {
+		t.Fatalf("RunInThreadStream failed: %v", err)
+	}
+	defer func() { _ = stream.Close() }()
+
+	if event, err := stream.Next(); err != nil || event.Status.Status != "processing" {
+		t.Fatalf("unexpected first event %+v, %v", event, err)
+	}
+	if _, err := stream.Next(); err != io.ErrUnexpectedEOF {
+		t.Errorf("expected io.ErrUnexpectedEOF for a dropped stream, got %v", err)
+	}
+}
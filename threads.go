@@ -3,24 +3,46 @@ package taskforceai
 import (
 	"context"
 	"fmt"
+	"net/url"
+	"strconv"
 	"time"
 )
 
 // Thread represents a conversation thread.
 type Thread struct {
-	ID        int       `json:"id"`
-	Title     string    `json:"title"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID            int            `json:"id"`
+	Title         string         `json:"title"`
+	Metadata      map[string]any `json:"metadata,omitempty"`
+	MessageCount  int            `json:"message_count,omitempty"`
+	LastMessageAt time.Time      `json:"last_message_at,omitzero"` // zero if the thread has no messages
+	CreatedAt     time.Time      `json:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at"`
 }
 
 // ThreadMessage represents a message within a thread.
 type ThreadMessage struct {
-	ID        int       `json:"id"`
-	ThreadID  int       `json:"thread_id"`
-	Role      string    `json:"role"` // "user" or "assistant"
-	Content   string    `json:"content"`
-	CreatedAt time.Time `json:"created_at"`
+	ID          int          `json:"id,omitempty"`        // unset when creating a message
+	ThreadID    int          `json:"thread_id,omitempty"` // unset when creating a message
+	Role        string       `json:"role"`                // "user", "assistant" or "system"
+	Content     string       `json:"content"`
+	Attachments []Attachment `json:"attachments,omitempty"`
+	CreatedAt   time.Time    `json:"created_at,omitzero"` // unset to let the server assign it
+}
+
+// Message roles.
+const (
+	MessageRoleUser      = "user"
+	MessageRoleAssistant = "assistant"
+	MessageRoleSystem    = "system"
+)
+
+// UpdateThreadMessageOptions contains the changes to apply to a message.
+// Empty or nil fields are left unchanged; a non-nil empty Attachments
+// removes all attachments.
+type UpdateThreadMessageOptions struct {
+	Role        string       `json:"role,omitempty"`
+	Content     *string      `json:"content,omitempty"`
+	Attachments []Attachment `json:"attachments,omitempty"`
 }
 
 // CreateThreadOptions contains options for creating a thread.
@@ -30,10 +52,77 @@ type CreateThreadOptions struct {
 	Metadata map[string]any  `json:"metadata,omitempty"`
 }
 
+// UpdateThreadOptions contains the changes to apply to a thread. Nil fields
+// are left unchanged.
+type UpdateThreadOptions struct {
+	Title *string `json:"title,omitempty"`
+	// Metadata is merged into the thread's metadata; a nil value removes
+	// the key. If ReplaceMetadata is set, Metadata replaces it entirely.
+	Metadata        map[string]any `json:"metadata,omitempty"`
+	ReplaceMetadata bool           `json:"-"`
+}
+
 // ThreadListResponse contains a list of threads.
 type ThreadListResponse struct {
-	Threads []Thread `json:"threads"`
-	Total   int      `json:"total"`
+	Threads    []Thread `json:"threads"`
+	Total      int      `json:"total"`
+	NextCursor string   `json:"next_cursor,omitempty"`
+}
+
+// ThreadSortField is a field threads can be sorted by.
+type ThreadSortField string
+
+const (
+	ThreadSortCreatedAt ThreadSortField = "created_at"
+	ThreadSortUpdatedAt ThreadSortField = "updated_at"
+)
+
+// ListThreadsOptions filters and sorts a thread listing. Zero values are
+// omitted.
+type ListThreadsOptions struct {
+	Limit         int
+	Offset        int
+	Cursor        string // from ThreadListResponse.NextCursor; takes precedence over Offset
+	Title         string // case-insensitive substring of the title
+	Metadata      map[string]string
+	UpdatedAfter  time.Time
+	UpdatedBefore time.Time
+	SortBy        ThreadSortField
+	Order         SortOrder
+}
+
+func (o *ListThreadsOptions) query() url.Values {
+	q := url.Values{}
+	if o == nil {
+		return q
+	}
+	if o.Limit > 0 {
+		q.Set("limit", strconv.Itoa(o.Limit))
+	}
+	if o.Cursor != "" {
+		q.Set("cursor", o.Cursor)
+	} else if o.Offset > 0 {
+		q.Set("offset", strconv.Itoa(o.Offset))
+	}
+	if o.Title != "" {
+		q.Set("title", o.Title)
+	}
+	for key, value := range o.Metadata {
+		q.Set("metadata."+key, value)
+	}
+	if !o.UpdatedAfter.IsZero() {
+		q.Set("updated_after", o.UpdatedAfter.UTC().Format(time.RFC3339))
+	}
+	if !o.UpdatedBefore.IsZero() {
+		q.Set("updated_before", o.UpdatedBefore.UTC().Format(time.RFC3339))
+	}
+	if o.SortBy != "" {
+		q.Set("sort", string(o.SortBy))
+	}
+	if o.Order != "" {
+		q.Set("order", string(o.Order))
+	}
+	return q
 }
 
 // ThreadMessagesResponse contains messages from a thread.
@@ -44,9 +133,12 @@ type ThreadMessagesResponse struct {
 
 // ThreadRunOptions contains options for running a prompt in a thread.
 type ThreadRunOptions struct {
-	Prompt  string                 `json:"prompt"`
-	ModelID string                 `json:"model_id,omitempty"`
-	Options map[string]interface{} `json:"options,omitempty"`
+	Prompt      string                 `json:"prompt"`
+	ModelID     string                 `json:"model_id,omitempty"`
+	Options     map[string]interface{} `json:"options,omitempty"`
+	Attachments []Attachment           `json:"attachments,omitempty"`
+	// ContextPolicy, if set, compacts the thread's history before the run.
+	ContextPolicy *ContextPolicy `json:"-"`
 }
 
 // ThreadRunResponse contains the result of running in a thread.
@@ -71,40 +163,28 @@ func (c *Client) CreateThread(ctx context.Context, opts *CreateThreadOptions) (*
 		}
 	}
 
-	resp, err := c.doRequest(ctx, "POST", "/threads", body)
+	thread, err := do[Thread](ctx, c, "POST", "/threads", body, "create thread")
 	if err != nil {
 		return nil, err
 	}
-	defer func() { _ = resp.Body.Close() }()
-
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return nil, fmt.Errorf("failed to create thread: status %d", resp.StatusCode)
-	}
-
-	var thread Thread
-	if err := decodeJSON(resp.Body, &thread); err != nil {
-		return nil, err
-	}
 
 	return &thread, nil
 }
 
 // ListThreads retrieves a list of threads.
 func (c *Client) ListThreads(ctx context.Context, limit, offset int) (*ThreadListResponse, error) {
-	path := fmt.Sprintf("/threads?limit=%d&offset=%d", limit, offset)
-
-	resp, err := c.doRequest(ctx, "GET", path, nil)
-	if err != nil {
-		return nil, err
-	}
-	defer func() { _ = resp.Body.Close() }()
+	return c.ListThreadsWithOptions(ctx, &ListThreadsOptions{Limit: limit, Offset: offset})
+}
 
-	if resp.StatusCode != 200 {
-		return nil, fmt.Errorf("failed to list threads: status %d", resp.StatusCode)
+// ListThreadsWithOptions retrieves a filtered, sorted list of threads.
+func (c *Client) ListThreadsWithOptions(ctx context.Context, opts *ListThreadsOptions) (*ThreadListResponse, error) {
+	path := "/threads"
+	if q := opts.query(); len(q) > 0 {
+		path += "?" + q.Encode()
 	}
 
-	var result ThreadListResponse
-	if err := decodeJSON(resp.Body, &result); err != nil {
+	result, err := do[ThreadListResponse](ctx, c, "GET", path, nil, "list threads")
+	if err != nil {
 		return nil, err
 	}
 
@@ -115,18 +195,39 @@ func (c *Client) ListThreads(ctx context.Context, limit, offset int) (*ThreadLis
 func (c *Client) GetThread(ctx context.Context, threadID int) (*Thread, error) {
 	path := fmt.Sprintf("/threads/%d", threadID)
 
-	resp, err := c.doRequest(ctx, "GET", path, nil)
+	thread, err := do[Thread](ctx, c, "GET", path, nil, "get thread")
 	if err != nil {
 		return nil, err
 	}
-	defer func() { _ = resp.Body.Close() }()
 
-	if resp.StatusCode != 200 {
-		return nil, fmt.Errorf("failed to get thread: status %d", resp.StatusCode)
+	return &thread, nil
+}
+
+// UpdateThread renames a thread or changes its metadata.
+func (c *Client) UpdateThread(ctx context.Context, threadID int, opts UpdateThreadOptions) (*Thread, error) {
+	body := map[string]interface{}{}
+	if opts.Title != nil {
+		body["title"] = *opts.Title
+	}
+	if opts.ReplaceMetadata {
+		metadata := opts.Metadata
+		if metadata == nil {
+			metadata = map[string]any{}
+		}
+		body["metadata"] = metadata
+		body["metadata_mode"] = "replace"
+	} else if len(opts.Metadata) > 0 {
+		body["metadata"] = opts.Metadata
+		body["metadata_mode"] = "merge"
 	}
+	if len(body) == 0 {
+		return nil, fmt.Errorf("nothing to update")
+	}
+
+	path := fmt.Sprintf("/threads/%d", threadID)
 
-	var thread Thread
-	if err := decodeJSON(resp.Body, &thread); err != nil {
+	thread, err := do[Thread](ctx, c, "PATCH", path, body, "update thread")
+	if err != nil {
 		return nil, err
 	}
 
@@ -137,39 +238,89 @@ func (c *Client) GetThread(ctx context.Context, threadID int) (*Thread, error) {
 func (c *Client) DeleteThread(ctx context.Context, threadID int) error {
 	path := fmt.Sprintf("/threads/%d", threadID)
 
-	resp, err := c.doRequest(ctx, "DELETE", path, nil)
-	if err != nil {
-		return err
-	}
-	defer func() { _ = resp.Body.Close() }()
-
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return fmt.Errorf("failed to delete thread: status %d", resp.StatusCode)
-	}
-
-	return nil
+	return doNoContent(ctx, c, "DELETE", path, nil, "delete thread")
 }
 
 // GetThreadMessages retrieves messages from a thread.
 func (c *Client) GetThreadMessages(ctx context.Context, threadID int, limit, offset int) (*ThreadMessagesResponse, error) {
 	path := fmt.Sprintf("/threads/%d/messages?limit=%d&offset=%d", threadID, limit, offset)
 
-	resp, err := c.doRequest(ctx, "GET", path, nil)
+	result, err := do[ThreadMessagesResponse](ctx, c, "GET", path, nil, "get thread messages")
 	if err != nil {
 		return nil, err
 	}
-	defer func() { _ = resp.Body.Close() }()
 
-	if resp.StatusCode != 200 {
-		return nil, fmt.Errorf("failed to get thread messages: status %d", resp.StatusCode)
-	}
+	return &result, nil
+}
+
+// GetThreadMessage retrieves a single message from a thread.
+func (c *Client) GetThreadMessage(ctx context.Context, threadID, messageID int) (*ThreadMessage, error) {
+	path := fmt.Sprintf("/threads/%d/messages/%d", threadID, messageID)
 
-	var result ThreadMessagesResponse
-	if err := decodeJSON(resp.Body, &result); err != nil {
+	message, err := do[ThreadMessage](ctx, c, "GET", path, nil, "get thread message")
+	if err != nil {
 		return nil, err
 	}
 
-	return &result, nil
+	return &message, nil
+}
+
+// Page sizes used to read every thread or a whole thread.
+const (
+	listThreadsPageSize    = 100
+	threadMessagesPageSize = 100
+)
+
+// listAllThreads pages through ListThreadsWithOptions and returns every
+// thread matching filter, which may be nil.
+func (c *Client) listAllThreads(ctx context.Context, filter *ListThreadsOptions) ([]Thread, error) {
+	opts := ListThreadsOptions{}
+	if filter != nil {
+		opts = *filter
+	}
+	opts.Limit, opts.Offset, opts.Cursor = listThreadsPageSize, 0, ""
+
+	var all []Thread
+	for {
+		page, err := c.ListThreadsWithOptions(ctx, &opts)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, page.Threads...)
+		if page.NextCursor != "" {
+			opts.Cursor = page.NextCursor
+			continue
+		}
+		if opts.Cursor != "" {
+			return all, nil
+		}
+		opts.Offset += len(page.Threads)
+		if len(page.Threads) < listThreadsPageSize || (page.Total > 0 && opts.Offset >= page.Total) {
+			return all, nil
+		}
+	}
+}
+
+// listAllThreadMessages pages through GetThreadMessages and returns every
+// message of the thread in order.
+func (c *Client) listAllThreadMessages(ctx context.Context, threadID int) ([]ThreadMessage, error) {
+	return c.listThreadMessagesFrom(ctx, threadID, 0)
+}
+
+// listThreadMessagesFrom returns the messages of a thread after the first
+// offset, in order. A response without a total is paged until a short page.
+func (c *Client) listThreadMessagesFrom(ctx context.Context, threadID, offset int) ([]ThreadMessage, error) {
+	var messages []ThreadMessage
+	for {
+		page, err := c.GetThreadMessages(ctx, threadID, threadMessagesPageSize, offset+len(messages))
+		if err != nil {
+			return nil, err
+		}
+		messages = append(messages, page.Messages...)
+		if len(page.Messages) < threadMessagesPageSize || (page.Total > 0 && offset+len(messages) >= page.Total) {
+			return messages, nil
+		}
+	}
 }
 
 // RunInThread submits a prompt within a thread context.
@@ -178,6 +329,10 @@ func (c *Client) RunInThread(ctx context.Context, threadID int, opts ThreadRunOp
 		return nil, fmt.Errorf("prompt is required")
 	}
 
+	if _, err := c.applyContextPolicy(ctx, threadID, opts.ModelID, opts.Prompt, opts.ContextPolicy); err != nil {
+		return nil, err
+	}
+
 	path := fmt.Sprintf("/threads/%d/runs", threadID)
 	body := map[string]interface{}{
 		"prompt": opts.Prompt,
@@ -188,21 +343,101 @@ func (c *Client) RunInThread(ctx context.Context, threadID int, opts ThreadRunOp
 	if len(opts.Options) > 0 {
 		body["options"] = opts.Options
 	}
+	if len(opts.Attachments) > 0 {
+		body["attachments"] = opts.Attachments
+	}
 
-	resp, err := c.doRequest(ctx, "POST", path, body)
+	result, err := do[ThreadRunResponse](ctx, c, "POST", path, body, "run in thread")
 	if err != nil {
 		return nil, err
 	}
-	defer func() { _ = resp.Body.Close() }()
 
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return nil, fmt.Errorf("failed to run in thread: status %d", resp.StatusCode)
+	return &result, nil
+}
+
+// AddThreadMessages appends messages to a thread without running the model,
+// for example to seed it with imported history. Only Role, Content,
+// Attachments and a non-zero CreatedAt are sent. The stored messages are
+// returned.
+func (c *Client) AddThreadMessages(ctx context.Context, threadID int, messages []ThreadMessage) ([]ThreadMessage, error) {
+	if len(messages) == 0 {
+		return nil, fmt.Errorf("at least one message is required")
 	}
 
-	var result ThreadRunResponse
-	if err := decodeJSON(resp.Body, &result); err != nil {
+	items := make([]map[string]interface{}, 0, len(messages))
+	for i, m := range messages {
+		if err := validateMessageRole(m.Role); err != nil {
+			return nil, fmt.Errorf("message %d: %w", i, err)
+		}
+		item := map[string]interface{}{
+			"role":    m.Role,
+			"content": m.Content,
+		}
+		if len(m.Attachments) > 0 {
+			item["attachments"] = m.Attachments
+		}
+		if !m.CreatedAt.IsZero() {
+			item["created_at"] = m.CreatedAt
+		}
+		items = append(items, item)
+	}
+
+	path := fmt.Sprintf("/threads/%d/messages", threadID)
+
+	result, err := do[ThreadMessagesResponse](ctx, c, "POST", path, map[string]interface{}{"messages": items}, "add thread messages")
+	if err != nil {
 		return nil, err
 	}
 
-	return &result, nil
+	return result.Messages, nil
+}
+
+// UpdateThreadMessage edits a message in a thread.
+func (c *Client) UpdateThreadMessage(ctx context.Context, threadID, messageID int, opts UpdateThreadMessageOptions) (*ThreadMessage, error) {
+	if opts.Role != "" {
+		if err := validateMessageRole(opts.Role); err != nil {
+			return nil, err
+		}
+	}
+	if opts.Role == "" && opts.Content == nil && opts.Attachments == nil {
+		return nil, fmt.Errorf("nothing to update")
+	}
+
+	body := map[string]interface{}{}
+	if opts.Role != "" {
+		body["role"] = opts.Role
+	}
+	if opts.Content != nil {
+		body["content"] = *opts.Content
+	}
+	if opts.Attachments != nil {
+		body["attachments"] = opts.Attachments
+	}
+
+	path := fmt.Sprintf("/threads/%d/messages/%d", threadID, messageID)
+
+	message, err := do[ThreadMessage](ctx, c, "PATCH", path, body, "update thread message")
+	if err != nil {
+		return nil, err
+	}
+
+	return &message, nil
+}
+
+// DeleteThreadMessage removes a message from a thread.
+func (c *Client) DeleteThreadMessage(ctx context.Context, threadID, messageID int) error {
+	path := fmt.Sprintf("/threads/%d/messages/%d", threadID, messageID)
+
+	return doNoContent(ctx, c, "DELETE", path, nil, "delete thread message")
+}
+
+func validateMessageRole(role string) error {
+	switch role {
+	case MessageRoleUser, MessageRoleAssistant, MessageRoleSystem:
+		return nil
+	case "":
+		return fmt.Errorf("role is required")
+	default:
+		return fmt.Errorf("invalid role %q", role)
+	}
 }
@@ -0,0 +1,224 @@
+package taskforceai
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestClient_UpdateThread(t *testing.T) {
+	var got map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "PATCH" || r.URL.Path != "/threads/7" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		got = nil
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		_ = json.NewEncoder(w).Encode(Thread{ID: 7, Title: "renamed", Metadata: map[string]any{"project": "q3"}})
+	}))
+	defer server.Close()
+	client := NewClient(TaskForceAIOptions{BaseURL: server.URL})
+
+	title := "renamed"
+	thread, err := client.UpdateThread(context.Background(), 7, UpdateThreadOptions{Title: &title, Metadata: map[string]any{"project": "q3"}})
+	if err != nil {
+		t.Fatalf("UpdateThread failed: %v", err)
+	}
+	if thread.Title != "renamed" || thread.Metadata["project"] != "q3" {
+		t.Errorf("unexpected thread %+v", thread)
+	}
+	if got["title"] != "renamed" || got["metadata_mode"] != "merge" {
+		t.Errorf("unexpected body %v", got)
+	}
+
+	if _, err := client.UpdateThread(context.Background(), 7, UpdateThreadOptions{ReplaceMetadata: true}); err != nil {
+		t.Fatalf("replace failed: %v", err)
+	}
+	if got["metadata_mode"] != "replace" || len(got["metadata"].(map[string]any)) != 0 || got["title"] != nil {
+		t.Errorf("unexpected replace body %v", got)
+	}
+
+	if _, err := client.UpdateThread(context.Background(), 7, UpdateThreadOptions{}); err == nil {
+		t.Error("expected error for empty update")
+	}
+}
+
+func TestClient_AddThreadMessages(t *testing.T) {
+	var got struct {
+		Messages []map[string]any `json:"messages"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || r.URL.Path != "/threads/3/messages" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		_ = json.NewEncoder(w).Encode(ThreadMessagesResponse{Messages: []ThreadMessage{{ID: 1, ThreadID: 3, Role: "system"}, {ID: 2, ThreadID: 3, Role: "user"}}, Total: 2})
+	}))
+	defer server.Close()
+	client := NewClient(TaskForceAIOptions{BaseURL: server.URL})
+
+	messages, err := client.AddThreadMessages(context.Background(), 3, []ThreadMessage{
+		{Role: MessageRoleSystem, Content: "be brief"},
+		{Role: MessageRoleUser, Content: "hi"},
+	})
+	if err != nil {
+		t.Fatalf("AddThreadMessages failed: %v", err)
+	}
+	if len(messages) != 2 || len(got.Messages) != 2 {
+		t.Fatalf("unexpected result %+v, sent %v", messages, got.Messages)
+	}
+	if _, ok := got.Messages[0]["id"]; ok || got.Messages[0]["role"] != "system" {
+		t.Errorf("unexpected message body %v", got.Messages[0])
+	}
+
+	if _, err := client.AddThreadMessages(context.Background(), 3, []ThreadMessage{{Role: "tool", Content: "x"}}); err == nil {
+		t.Error("expected error for invalid role")
+	}
+}
+
+func TestClient_UpdateThreadMessage(t *testing.T) {
+	var got map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "PATCH" {
+			t.Errorf("unexpected method %s", r.Method)
+		}
+		if r.URL.Path != "/threads/3/messages/9" {
+			http.Error(w, `{"error": "message not found"}`, http.StatusNotFound)
+			return
+		}
+		got = nil
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		_ = json.NewEncoder(w).Encode(ThreadMessage{ID: 9, ThreadID: 3, Role: "assistant", Content: "edited"})
+	}))
+	defer server.Close()
+	client := NewClient(TaskForceAIOptions{BaseURL: server.URL})
+
+	content := "edited"
+	message, err := client.UpdateThreadMessage(context.Background(), 3, 9, UpdateThreadMessageOptions{Content: &content})
+	if err != nil {
+		t.Fatalf("UpdateThreadMessage failed: %v", err)
+	}
+	if message.ID != 9 || message.Content != "edited" {
+		t.Errorf("unexpected message %+v", message)
+	}
+	if len(got) != 1 || got["content"] != "edited" {
+		t.Errorf("expected only content to be sent, got %v", got)
+	}
+
+	// A non-nil empty slice clears the attachments.
+	if _, err := client.UpdateThreadMessage(context.Background(), 3, 9, UpdateThreadMessageOptions{Role: MessageRoleSystem, Attachments: []Attachment{}}); err != nil {
+		t.Fatal(err)
+	}
+	if attachments, ok := got["attachments"].([]any); !ok || len(attachments) != 0 || got["role"] != "system" {
+		t.Errorf("unexpected body %v", got)
+	}
+
+	if _, err := client.UpdateThreadMessage(context.Background(), 3, 9, UpdateThreadMessageOptions{}); err == nil {
+		t.Error("expected error for empty update")
+	}
+	if _, err := client.UpdateThreadMessage(context.Background(), 3, 9, UpdateThreadMessageOptions{Role: "tool"}); err == nil {
+		t.Error("expected error for invalid role")
+	}
+	_, err = client.UpdateThreadMessage(context.Background(), 3, 10, UpdateThreadMessageOptions{Content: &content})
+	if !isStatus(err, http.StatusNotFound) || err.Error() != "failed to update thread message: status 404: message not found" {
+		t.Errorf("expected 404 error, got %v", err)
+	}
+}
+
+func TestClient_DeleteThreadMessage(t *testing.T) {
+	var deleted []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "DELETE" {
+			t.Errorf("unexpected method %s", r.Method)
+		}
+		if r.URL.Path != "/threads/3/messages/9" {
+			http.Error(w, `{"error": "message not found"}`, http.StatusNotFound)
+			return
+		}
+		deleted = append(deleted, r.URL.Path)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+	client := NewClient(TaskForceAIOptions{BaseURL: server.URL})
+
+	if err := client.DeleteThreadMessage(context.Background(), 3, 9); err != nil {
+		t.Fatalf("DeleteThreadMessage failed: %v", err)
+	}
+	if len(deleted) != 1 {
+		t.Errorf("expected one delete, got %v", deleted)
+	}
+	err := client.DeleteThreadMessage(context.Background(), 3, 10)
+	if !isStatus(err, http.StatusNotFound) || err.Error() != "failed to delete thread message: status 404: message not found" {
+		t.Errorf("expected 404 error, got %v", err)
+	}
+}
+
+func TestClient_ListThreadsWithOptions(t *testing.T) {
+	var queries []url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.Query())
+		resp := ThreadListResponse{Threads: []Thread{{ID: 1, MessageCount: 4}}, NextCursor: "page-2"}
+		if r.URL.Query().Get("cursor") == "page-2" {
+			resp = ThreadListResponse{Threads: []Thread{{ID: 2}}}
+		}
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+	client := NewClient(TaskForceAIOptions{BaseURL: server.URL})
+
+	after := time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)
+	threads, err := client.listAllThreads(context.Background(), &ListThreadsOptions{
+		Title:        "billing",
+		Metadata:     map[string]string{"team": "payments"},
+		UpdatedAfter: after,
+		SortBy:       ThreadSortUpdatedAt,
+		Order:        SortDesc,
+	})
+	if err != nil {
+		t.Fatalf("listAllThreads failed: %v", err)
+	}
+	if len(threads) != 2 || threads[0].MessageCount != 4 || len(queries) != 2 {
+		t.Fatalf("unexpected threads %+v after %d requests", threads, len(queries))
+	}
+
+	q := queries[0]
+	if q.Get("title") != "billing" || q.Get("metadata.team") != "payments" || q.Get("updated_after") != "2026-04-01T00:00:00Z" ||
+		q.Get("sort") != "updated_at" || q.Get("order") != "desc" || q.Get("limit") != "100" {
+		t.Errorf("unexpected query %v", q)
+	}
+	if queries[1].Get("cursor") != "page-2" || queries[1].Get("title") != "billing" {
+		t.Errorf("unexpected second query %v", queries[1])
+	}
+}
+
+func TestClient_ListAllThreadMessages_WithoutTotal(t *testing.T) {
+	var offsets []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		offsets = append(offsets, r.URL.Query().Get("offset"))
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		var page []ThreadMessage
+		for id := offset + 1; id <= 150 && len(page) < 100; id++ {
+			page = append(page, ThreadMessage{ID: id, ThreadID: 5})
+		}
+		// No total, as some deployments omit it.
+		_ = json.NewEncoder(w).Encode(map[string]any{"messages": page})
+	}))
+	defer server.Close()
+	client := NewClient(TaskForceAIOptions{BaseURL: server.URL})
+
+	messages, err := client.listAllThreadMessages(context.Background(), 5)
+	if err != nil {
+		t.Fatalf("listAllThreadMessages failed: %v", err)
+	}
+	if len(messages) != 150 || messages[149].ID != 150 {
+		t.Errorf("expected 150 messages, got %d", len(messages))
+	}
+	if len(offsets) != 2 || offsets[1] != "100" {
+		t.Errorf("unexpected offsets %v", offsets)
+	}
+}
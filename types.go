@@ -11,6 +11,9 @@ type TaskForceAIOptions struct {
 	Timeout      time.Duration
 	ResponseHook func(statusCode int, header map[string][]string)
 	MockMode     bool
+	// MaxResponseBytes limits the size of JSON response bodies (default:
+	// DefaultMaxResponseBytes).
+	MaxResponseBytes int64
 }
 
 // TaskSubmissionOptions defines parameters for submitting a task.
@@ -20,16 +23,30 @@ type TaskSubmissionOptions struct {
 	Mock        bool                   `json:"mock,omitempty"`
 	VercelAIKey string                 `json:"vercelAiKey,omitempty"`
 	Metadata    map[string]interface{} `json:"metadata,omitempty"`
+	// CallbackURL receives signed task.completed and task.failed events
+	// (see the webhook package) instead of requiring the task to be polled.
+	CallbackURL string `json:"callbackUrl,omitempty"`
+	// Attachments references uploaded files the task can use.
+	Attachments []Attachment `json:"attachments,omitempty"`
 }
 
 // TaskStatus represents the current state of a task.
 type TaskStatus struct {
-	TaskID   string                 `json:"taskId"`
-	Status   string                 `json:"status"` // "processing", "completed", "failed"
-	Result   *string                `json:"result,omitempty"`
-	Error    *string                `json:"error,omitempty"`
-	Warnings []string               `json:"warnings,omitempty"`
-	Metadata map[string]interface{} `json:"metadata,omitempty"`
+	TaskID    string                 `json:"taskId"`
+	Status    string                 `json:"status"` // "processing", "completed", "failed"
+	Result    *string                `json:"result,omitempty"`
+	Error     *string                `json:"error,omitempty"`
+	Warnings  []string               `json:"warnings,omitempty"`
+	Metadata  map[string]interface{} `json:"metadata,omitempty"`
+	Artifacts []TaskArtifact         `json:"artifacts,omitempty"`
+}
+
+// TaskArtifact references a file produced by a task.
+type TaskArtifact struct {
+	FileID   string `json:"fileId"`
+	Filename string `json:"filename"`
+	MimeType string `json:"mimeType,omitempty"`
+	Bytes    int64  `json:"bytes,omitempty"`
 }
 
 // TaskResult is a completed TaskStatus.
@@ -0,0 +1,476 @@
+package taskforceai
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultChunkSize         = 8 << 20
+	DefaultUploadConcurrency = 4
+	DefaultChunkRetries      = 3
+)
+
+// UploadSession tracks a resumable upload. It is persisted as JSON at
+// ResumableUploadOptions.SessionPath between attempts.
+type UploadSession struct {
+	ID        string       `json:"id"`
+	Filename  string       `json:"filename"`
+	Bytes     int64        `json:"bytes"`
+	ChunkSize int64        `json:"chunk_size"`
+	Purpose   string       `json:"purpose,omitempty"`
+	MimeType  string       `json:"mime_type,omitempty"`
+	Parts     []UploadPart `json:"parts,omitempty"` // completed parts
+	ExpiresAt time.Time    `json:"expires_at,omitempty"`
+}
+
+// UploadPart describes one uploaded chunk of a resumable upload.
+type UploadPart struct {
+	PartNumber int    `json:"part_number"`
+	Bytes      int64  `json:"bytes"`
+	SHA256     string `json:"sha256"`
+}
+
+// ResumableUploadOptions contains options for a chunked, resumable upload.
+type ResumableUploadOptions struct {
+	FileUploadOptions
+
+	// ChunkSize is the size of each part (default: 8 MiB).
+	ChunkSize int64
+	// Concurrency is the number of parts uploaded in parallel (default: 4).
+	Concurrency int
+	// MaxRetries is the number of retries per part (default: 3).
+	MaxRetries int
+	// SessionPath, if set, persists the upload session to this file after
+	// every completed part so an interrupted upload resumes where it stopped.
+	// The file is removed once the upload is finalized.
+	SessionPath string
+}
+
+var errUploadSessionNotFound = errors.New("upload session not found")
+
+// errRetryable marks a part failure worth retrying.
+type errRetryable struct{ err error }
+
+func (e errRetryable) Error() string { return e.err.Error() }
+func (e errRetryable) Unwrap() error { return e.err }
+
+// UploadFileResumable uploads size bytes of content in parts. Parts are
+// uploaded in parallel, verified with SHA-256 checksums and retried
+// individually, then finalized into a File. Pass an *os.File together with
+// its Stat().Size() to upload a local file.
+func (c *Client) UploadFileResumable(ctx context.Context, filename string, content io.ReaderAt, size int64, opts *ResumableUploadOptions) (*File, error) {
+	if filename == "" {
+		return nil, fmt.Errorf("filename is required")
+	}
+	if size < 0 {
+		return nil, fmt.Errorf("size must not be negative")
+	}
+
+	o := ResumableUploadOptions{}
+	if opts != nil {
+		o = *opts
+	}
+	if o.ChunkSize <= 0 {
+		o.ChunkSize = DefaultChunkSize
+	}
+	if o.Concurrency <= 0 {
+		o.Concurrency = DefaultUploadConcurrency
+	}
+	if o.MaxRetries <= 0 {
+		o.MaxRetries = DefaultChunkRetries
+	}
+
+	session, err := c.openUploadSession(ctx, filename, size, &o)
+	if err != nil {
+		return nil, err
+	}
+
+	u := &chunkedUpload{
+		client:   c,
+		content:  content,
+		session:  session,
+		path:     o.SessionPath,
+		retries:  o.MaxRetries,
+		progress: newProgressTracker(o.Progress, o.ProgressInterval, size),
+	}
+	if err := u.dropChangedParts(); err != nil {
+		return nil, err
+	}
+	u.progress.resume(u.completedBytes())
+	if err := u.save(); err != nil {
+		return nil, err
+	}
+
+	if err := u.uploadParts(ctx, o.Concurrency); err != nil {
+		return nil, err
+	}
+
+	file, err := c.completeUpload(ctx, session)
+	if err != nil {
+		return nil, err
+	}
+
+	u.progress.finish()
+
+	if o.SessionPath != "" {
+		_ = os.Remove(o.SessionPath)
+	}
+	return file, nil
+}
+
+// AbortUpload cancels a resumable upload and discards its uploaded parts.
+func (c *Client) AbortUpload(ctx context.Context, uploadID string) error {
+	path := "/uploads/" + uploadID
+
+	return doNoContent(ctx, c, "DELETE", path, nil, "abort upload")
+}
+
+// openUploadSession resumes the session persisted at opts.SessionPath when it
+// still matches the upload and exists on the server, and creates a new one
+// otherwise.
+func (c *Client) openUploadSession(ctx context.Context, filename string, size int64, opts *ResumableUploadOptions) (*UploadSession, error) {
+	if opts.SessionPath != "" {
+		saved, err := loadUploadSession(opts.SessionPath)
+		if err != nil {
+			return nil, err
+		}
+		if saved != nil && saved.ID != "" && saved.Filename == filename && saved.Bytes == size {
+			remote, err := c.getUploadSession(ctx, saved.ID)
+			switch {
+			case err == nil:
+				// The server is authoritative about which parts it holds;
+				// the saved checksums fill in any it does not report.
+				remote.Filename, remote.Bytes = saved.Filename, saved.Bytes
+				checksums := make(map[int]string, len(saved.Parts))
+				for _, p := range saved.Parts {
+					checksums[p.PartNumber] = p.SHA256
+				}
+				for i, p := range remote.Parts {
+					if p.SHA256 == "" {
+						remote.Parts[i].SHA256 = checksums[p.PartNumber]
+					}
+				}
+				if remote.ChunkSize == 0 {
+					remote.ChunkSize = saved.ChunkSize
+				}
+				return remote, nil
+			case !errors.Is(err, errUploadSessionNotFound):
+				return nil, err
+			}
+		}
+	}
+
+	return c.createUploadSession(ctx, filename, size, opts)
+}
+
+func (c *Client) createUploadSession(ctx context.Context, filename string, size int64, opts *ResumableUploadOptions) (*UploadSession, error) {
+	body := map[string]interface{}{
+		"filename":   filename,
+		"bytes":      size,
+		"chunk_size": opts.ChunkSize,
+	}
+	if opts.Purpose != "" {
+		body["purpose"] = opts.Purpose
+	}
+	if opts.MimeType != "" {
+		body["mime_type"] = opts.MimeType
+	}
+	if len(opts.Metadata) > 0 {
+		body["metadata"] = opts.Metadata
+	}
+
+	session, err := do[UploadSession](ctx, c, "POST", "/uploads", body, "create upload session")
+	if err != nil {
+		return nil, err
+	}
+	if session.ChunkSize == 0 {
+		session.ChunkSize = opts.ChunkSize
+	}
+	session.Filename, session.Bytes = filename, size
+
+	return &session, nil
+}
+
+func (c *Client) getUploadSession(ctx context.Context, uploadID string) (*UploadSession, error) {
+	path := "/uploads/" + uploadID
+
+	session, err := do[UploadSession](ctx, c, "GET", path, nil, "get upload session")
+	if isStatus(err, http.StatusNotFound) {
+		return nil, errUploadSessionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &session, nil
+}
+
+func (c *Client) completeUpload(ctx context.Context, session *UploadSession) (*File, error) {
+	path := "/uploads/" + session.ID + "/complete"
+
+	parts := make([]UploadPart, len(session.Parts))
+	copy(parts, session.Parts)
+	sort.Slice(parts, func(i, j int) bool { return parts[i].PartNumber < parts[j].PartNumber })
+
+	file, err := do[File](ctx, c, "POST", path, map[string]interface{}{"parts": parts}, "complete upload")
+	if err != nil {
+		return nil, err
+	}
+
+	return &file, nil
+}
+
+// chunkedUpload uploads the missing parts of a session.
+type chunkedUpload struct {
+	client   *Client
+	content  io.ReaderAt
+	path     string
+	retries  int
+	progress *progressTracker
+
+	mu      sync.Mutex
+	session *UploadSession
+}
+
+func (u *chunkedUpload) partCount() int {
+	n := int((u.session.Bytes + u.session.ChunkSize - 1) / u.session.ChunkSize)
+	if n == 0 {
+		n = 1
+	}
+	return n
+}
+
+func (u *chunkedUpload) partLength(n int) int64 {
+	offset := int64(n-1) * u.session.ChunkSize
+	length := u.session.ChunkSize
+	if remaining := u.session.Bytes - offset; remaining < length {
+		length = remaining
+	}
+	return length
+}
+
+func (u *chunkedUpload) completedBytes() int64 {
+	var total int64
+	for _, p := range u.session.Parts {
+		total += u.partLength(p.PartNumber)
+	}
+	return total
+}
+
+// dropChangedParts forgets completed parts whose content no longer matches
+// their checksum, or that have none, so they are uploaded again.
+func (u *chunkedUpload) dropChangedParts() error {
+	kept := u.session.Parts[:0]
+	for _, p := range u.session.Parts {
+		if p.PartNumber < 1 || p.PartNumber > u.partCount() || p.SHA256 == "" {
+			continue
+		}
+		offset := int64(p.PartNumber-1) * u.session.ChunkSize
+		hash := sha256.New()
+		if _, err := io.Copy(hash, io.NewSectionReader(u.content, offset, u.partLength(p.PartNumber))); err != nil {
+			return fmt.Errorf("failed to read part %d: %w", p.PartNumber, err)
+		}
+		if hex.EncodeToString(hash.Sum(nil)) == p.SHA256 {
+			kept = append(kept, p)
+		}
+	}
+	u.session.Parts = kept
+	return nil
+}
+
+func (u *chunkedUpload) pendingParts() []int {
+	done := make(map[int]bool, len(u.session.Parts))
+	for _, p := range u.session.Parts {
+		done[p.PartNumber] = true
+	}
+
+	var pending []int
+	for n := 1; n <= u.partCount(); n++ {
+		if !done[n] {
+			pending = append(pending, n)
+		}
+	}
+	return pending
+}
+
+func (u *chunkedUpload) uploadParts(ctx context.Context, concurrency int) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	parts := make(chan int)
+	var wg sync.WaitGroup
+	var once sync.Once
+	var firstErr error
+
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for n := range parts {
+				if err := u.uploadPartWithRetry(ctx, n); err != nil {
+					once.Do(func() {
+						firstErr = err
+						cancel()
+					})
+				}
+			}
+		}()
+	}
+
+	for _, n := range u.pendingParts() {
+		select {
+		case parts <- n:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	close(parts)
+	wg.Wait()
+
+	if firstErr != nil {
+		return firstErr
+	}
+	return ctx.Err()
+}
+
+func (u *chunkedUpload) uploadPartWithRetry(ctx context.Context, n int) error {
+	offset := int64(n-1) * u.session.ChunkSize
+	length := u.partLength(n)
+
+	data := make([]byte, length)
+	if _, err := io.ReadFull(io.NewSectionReader(u.content, offset, length), data); err != nil {
+		return fmt.Errorf("failed to read part %d: %w", n, err)
+	}
+	sum := sha256.Sum256(data)
+	checksum := hex.EncodeToString(sum[:])
+
+	var err error
+	for attempt := 0; attempt <= u.retries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(chunkBackoff(attempt)):
+			}
+		}
+
+		err = u.putPart(ctx, n, data, checksum)
+		if err == nil {
+			return u.markDone(UploadPart{PartNumber: n, Bytes: length, SHA256: checksum})
+		}
+		var retryable errRetryable
+		if !errors.As(err, &retryable) || ctx.Err() != nil {
+			return err
+		}
+	}
+	return err
+}
+
+func (u *chunkedUpload) putPart(ctx context.Context, n int, data []byte, checksum string) error {
+	path := "/uploads/" + u.session.ID + "/parts/" + strconv.Itoa(n)
+
+	body := &progressReader{r: bytes.NewReader(data), tracker: u.progress}
+	req, err := u.client.newRequest(ctx, "PUT", path, body)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = int64(len(data))
+	req.Header.Set("Content-Type", "application/octet-stream")
+	req.Header.Set("X-Content-SHA256", checksum)
+
+	err = u.sendPart(req, n, checksum)
+	if err != nil {
+		// Bytes of a failed attempt will be sent again.
+		u.progress.add(-body.n.Load())
+	}
+	return err
+}
+
+func (u *chunkedUpload) sendPart(req *http.Request, n int, checksum string) error {
+	resp, err := u.client.send(req)
+	if err != nil {
+		return errRetryable{err}
+	}
+	defer closeResponse(resp)
+
+	if err := checkResponse(resp, "upload part "+strconv.Itoa(n)); err != nil {
+		if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500 {
+			return errRetryable{err}
+		}
+		return err
+	}
+
+	var part UploadPart
+	if err := u.client.decodeResponse(resp, &part); err == nil && part.SHA256 != "" && part.SHA256 != checksum {
+		return errRetryable{fmt.Errorf("checksum mismatch for part %d", n)}
+	}
+
+	return nil
+}
+
+func (u *chunkedUpload) markDone(part UploadPart) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	u.session.Parts = append(u.session.Parts, part)
+	return u.saveLocked()
+}
+
+func (u *chunkedUpload) save() error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.saveLocked()
+}
+
+func (u *chunkedUpload) saveLocked() error {
+	if u.path == "" {
+		return nil
+	}
+
+	data, err := json.Marshal(u.session)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(u.path, data)
+}
+
+func loadUploadSession(path string) (*UploadSession, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var session UploadSession
+	if err := json.Unmarshal(data, &session); err != nil {
+		// A corrupt session file only costs a restart of the upload.
+		return nil, nil
+	}
+	return &session, nil
+}
+
+// chunkBackoff returns the delay before retry attempt n (1-based).
+func chunkBackoff(attempt int) time.Duration {
+	d := 250 * time.Millisecond << (attempt - 1)
+	if d > 5*time.Second {
+		d = 5 * time.Second
+	}
+	return d
+}
@@ -0,0 +1,155 @@
+package taskforceai
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeUploadServer implements the resumable upload endpoints in memory.
+type fakeUploadServer struct {
+	mu        sync.Mutex
+	parts     map[int][]byte
+	failOnce  map[int]bool
+	rejectAll bool
+	assembled []byte
+	puts      []int
+}
+
+func (f *fakeUploadServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch {
+	case r.Method == "POST" && r.URL.Path == "/uploads":
+		_, _ = w.Write([]byte(`{"id": "up-1", "chunk_size": 4}`))
+	case r.Method == "GET" && r.URL.Path == "/uploads/up-1":
+		var parts []UploadPart
+		for n, data := range f.parts {
+			sum := sha256.Sum256(data)
+			parts = append(parts, UploadPart{PartNumber: n, Bytes: int64(len(data)), SHA256: hex.EncodeToString(sum[:])})
+		}
+		_ = json.NewEncoder(w).Encode(UploadSession{ID: "up-1", ChunkSize: 4, Parts: parts})
+	case r.Method == "PUT" && strings.HasPrefix(r.URL.Path, "/uploads/up-1/parts/"):
+		n, _ := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/uploads/up-1/parts/"))
+		if f.rejectAll {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if f.failOnce[n] {
+			delete(f.failOnce, n)
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		data, _ := io.ReadAll(r.Body)
+		f.parts[n] = data
+		f.puts = append(f.puts, n)
+		_ = json.NewEncoder(w).Encode(UploadPart{PartNumber: n, SHA256: r.Header.Get("X-Content-SHA256")})
+	case r.Method == "POST" && r.URL.Path == "/uploads/up-1/complete":
+		f.assembled = nil
+		for n := 1; n <= len(f.parts); n++ {
+			f.assembled = append(f.assembled, f.parts[n]...)
+		}
+		_, _ = w.Write([]byte(`{"id": "file-1", "filename": "data.bin", "bytes": ` + strconv.Itoa(len(f.assembled)) + `}`))
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func TestClient_UploadFileResumable(t *testing.T) {
+	fake := &fakeUploadServer{parts: map[int][]byte{}, failOnce: map[int]bool{2: true}}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	content := []byte("0123456789abcdefghij!")
+	var last TransferProgress
+	opts := &ResumableUploadOptions{ChunkSize: 4}
+	opts.Progress = func(p TransferProgress) { last = p }
+
+	client := NewClient(TaskForceAIOptions{BaseURL: server.URL})
+	file, err := client.UploadFileResumable(context.Background(), "data.bin", bytes.NewReader(content), int64(len(content)), opts)
+	if err != nil {
+		t.Fatalf("UploadFileResumable failed: %v", err)
+	}
+	if file.ID != "file-1" || !bytes.Equal(fake.assembled, content) {
+		t.Errorf("unexpected result %+v, assembled %q", file, fake.assembled)
+	}
+	if !last.Done || last.BytesTransferred != int64(len(content)) || last.TotalBytes != int64(len(content)) {
+		t.Errorf("unexpected final progress %+v", last)
+	}
+}
+
+func TestClient_UploadFileResumable_Resume(t *testing.T) {
+	content := []byte("0123456789abcdefghij!")
+	fake := &fakeUploadServer{parts: map[int][]byte{}, rejectAll: true}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	sessionPath := filepath.Join(t.TempDir(), "upload.json")
+	opts := &ResumableUploadOptions{ChunkSize: 4, SessionPath: sessionPath}
+	client := NewClient(TaskForceAIOptions{BaseURL: server.URL})
+
+	// 1. Non-retryable failure leaves the session on disk.
+	_, err := client.UploadFileResumable(context.Background(), "data.bin", bytes.NewReader(content), int64(len(content)), opts)
+	if err == nil || !strings.Contains(err.Error(), "status 400") {
+		t.Fatalf("expected part upload error, got %v", err)
+	}
+	if _, err := os.Stat(sessionPath); err != nil {
+		t.Fatalf("expected persisted session, got %v", err)
+	}
+
+	// 2. Resume uploads only the parts the server is missing.
+	fake.mu.Lock()
+	fake.rejectAll = false
+	fake.parts[1] = content[:4]
+	fake.parts[2] = content[4:8]
+	fake.mu.Unlock()
+
+	file, err := client.UploadFileResumable(context.Background(), "data.bin", bytes.NewReader(content), int64(len(content)), opts)
+	if err != nil {
+		t.Fatalf("resumed upload failed: %v", err)
+	}
+	if file.Bytes != int64(len(content)) || !bytes.Equal(fake.assembled, content) {
+		t.Errorf("unexpected resumed result %+v, assembled %q", file, fake.assembled)
+	}
+	if _, err := os.Stat(sessionPath); !os.IsNotExist(err) {
+		t.Errorf("expected session file to be removed, got %v", err)
+	}
+}
+
+func TestClient_UploadFileResumable_ChangedContent(t *testing.T) {
+	content := []byte("0123456789abcdefghij!")
+	fake := &fakeUploadServer{parts: map[int][]byte{1: content[:4], 2: []byte("XXXX")}}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	sessionPath := filepath.Join(t.TempDir(), "upload.json")
+	saved, _ := json.Marshal(UploadSession{ID: "up-1", Filename: "data.bin", Bytes: int64(len(content)), ChunkSize: 4})
+	if err := os.WriteFile(sessionPath, saved, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Part 2 on the server no longer matches the local content.
+	client := NewClient(TaskForceAIOptions{BaseURL: server.URL})
+	opts := &ResumableUploadOptions{ChunkSize: 4, Concurrency: 1, SessionPath: sessionPath}
+	if _, err := client.UploadFileResumable(context.Background(), "data.bin", bytes.NewReader(content), int64(len(content)), opts); err != nil {
+		t.Fatalf("resumed upload failed: %v", err)
+	}
+	if !bytes.Equal(fake.assembled, content) {
+		t.Errorf("unexpected assembled content %q", fake.assembled)
+	}
+	if len(fake.puts) != 5 || fake.puts[0] != 2 {
+		t.Errorf("expected parts 2-6 to be uploaded, got %v", fake.puts)
+	}
+}
@@ -0,0 +1,312 @@
+// Package webhook receives task events pushed by the TaskForceAI API.
+//
+// Each delivery is a JSON Event signed with HMAC-SHA256. The signature header
+// has the form "t=<unix seconds>,v1=<hex digest>", where the digest covers
+// "<timestamp>.<raw body>". Handler verifies the signature and timestamp,
+// rejects replayed deliveries and dispatches the decoded task to typed
+// callbacks. Every event must carry an ID, which is used to detect replays.
+package webhook
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	taskforceai "github.com/ClayWarren/taskforceai-sdk-go"
+)
+
+const (
+	// SignatureHeader carries the timestamp and signature of a delivery.
+	SignatureHeader = "X-TaskForceAI-Signature"
+
+	// DefaultTolerance is the maximum accepted age of a delivery.
+	DefaultTolerance = 5 * time.Minute
+
+	// DefaultMaxBodyBytes limits the size of a delivery body.
+	DefaultMaxBodyBytes = 1 << 20
+)
+
+// Event types sent by the API.
+const (
+	EventTaskCompleted = string(taskforceai.WebhookEventTaskCompleted)
+	EventTaskFailed    = string(taskforceai.WebhookEventTaskFailed)
+)
+
+var (
+	ErrMissingSignature = errors.New("webhook: missing signature")
+	ErrInvalidSignature = errors.New("webhook: invalid signature")
+	ErrTimestampExpired = errors.New("webhook: timestamp outside tolerance")
+	ErrReplayed         = errors.New("webhook: event already delivered")
+	ErrMissingEventID   = errors.New("webhook: event has no id")
+	ErrInFlight         = errors.New("webhook: event is still being handled")
+)
+
+// Event is a single webhook delivery.
+type Event struct {
+	ID        string                 `json:"id"`
+	Type      string                 `json:"type"`
+	CreatedAt time.Time              `json:"created_at"`
+	Task      taskforceai.TaskStatus `json:"data"`
+}
+
+// TaskHandler handles the task carried by an event. Returning an error makes
+// the receiver respond with 500 so the API retries the delivery.
+type TaskHandler func(ctx context.Context, task taskforceai.TaskStatus) error
+
+// HandlerOptions configures a Handler.
+type HandlerOptions struct {
+	// Secret is the signing secret of the callback or subscription.
+	Secret string
+	// PreviousSecrets are still accepted while a rotated secret rolls out.
+	PreviousSecrets []string
+	// Tolerance is the maximum age of a delivery (default: 5m).
+	Tolerance time.Duration
+	// MaxBodyBytes limits the request body size (default: 1 MiB).
+	MaxBodyBytes int64
+
+	OnCompleted TaskHandler
+	OnFailed    TaskHandler
+	// OnEvent receives every verified event, including types without a
+	// dedicated callback. It runs before the typed handlers.
+	OnEvent func(ctx context.Context, event Event) error
+}
+
+// Handler is an http.Handler that verifies and dispatches webhook events.
+type Handler struct {
+	opts    HandlerOptions
+	secrets []string
+	now     func() time.Time
+
+	mu       sync.Mutex
+	seen     map[string]time.Time // handled successfully
+	inFlight map[string]bool      // being dispatched
+}
+
+// NewHandler creates a webhook receiver.
+func NewHandler(opts HandlerOptions) *Handler {
+	if opts.Tolerance == 0 {
+		opts.Tolerance = DefaultTolerance
+	}
+	if opts.MaxBodyBytes == 0 {
+		opts.MaxBodyBytes = DefaultMaxBodyBytes
+	}
+
+	secrets := make([]string, 0, 1+len(opts.PreviousSecrets))
+	if opts.Secret != "" {
+		secrets = append(secrets, opts.Secret)
+	}
+	for _, s := range opts.PreviousSecrets {
+		if s != "" {
+			secrets = append(secrets, s)
+		}
+	}
+
+	return &Handler{
+		opts:     opts,
+		secrets:  secrets,
+		now:      time.Now,
+		seen:     map[string]time.Time{},
+		inFlight: map[string]bool{},
+	}
+}
+
+// ServeHTTP implements http.Handler.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	payload, err := io.ReadAll(io.LimitReader(r.Body, h.opts.MaxBodyBytes+1))
+	if err != nil {
+		http.Error(w, "failed to read body", http.StatusBadRequest)
+		return
+	}
+	if int64(len(payload)) > h.opts.MaxBodyBytes {
+		http.Error(w, "body too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	event, err := h.verifyAndDecode(payload, r.Header.Get(SignatureHeader))
+	switch {
+	case errors.Is(err, ErrReplayed):
+		// Already handled; acknowledge so the API stops retrying.
+		w.WriteHeader(http.StatusOK)
+		return
+	case errors.Is(err, ErrInFlight):
+		// The first delivery may still fail; ask the API to try again later.
+		w.Header().Set("Retry-After", "1")
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	case errors.Is(err, ErrMissingSignature), errors.Is(err, ErrInvalidSignature), errors.Is(err, ErrTimestampExpired):
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	case err != nil:
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	err = h.dispatch(r.Context(), event)
+	h.finish(event.ID, err == nil)
+	if err != nil {
+		http.Error(w, "handler error", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *Handler) verifyAndDecode(payload []byte, header string) (Event, error) {
+	now := h.now()
+	if err := verify(payload, header, h.secrets, h.opts.Tolerance, now); err != nil {
+		return Event{}, err
+	}
+
+	var event Event
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return Event{}, fmt.Errorf("webhook: invalid payload: %w", err)
+	}
+
+	if event.ID == "" {
+		return Event{}, ErrMissingEventID
+	}
+	if err := h.begin(event.ID, now); err != nil {
+		return Event{}, err
+	}
+
+	return event, nil
+}
+
+func (h *Handler) dispatch(ctx context.Context, event Event) error {
+	if h.opts.OnEvent != nil {
+		if err := h.opts.OnEvent(ctx, event); err != nil {
+			return err
+		}
+	}
+
+	switch event.Type {
+	case EventTaskCompleted:
+		if h.opts.OnCompleted != nil {
+			return h.opts.OnCompleted(ctx, event.Task)
+		}
+	case EventTaskFailed:
+		if h.opts.OnFailed != nil {
+			return h.opts.OnFailed(ctx, event.Task)
+		}
+	}
+	return nil
+}
+
+// begin marks an event ID as being dispatched. It fails with ErrReplayed if
+// the event was already handled and with ErrInFlight if another delivery of
+// it is still running. Handled IDs older than the tolerance window are
+// pruned, since their deliveries would be rejected by the timestamp check
+// anyway.
+func (h *Handler) begin(id string, now time.Time) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for seenID, at := range h.seen {
+		if now.Sub(at) > h.opts.Tolerance {
+			delete(h.seen, seenID)
+		}
+	}
+
+	if _, ok := h.seen[id]; ok {
+		return ErrReplayed
+	}
+	if h.inFlight[id] {
+		return ErrInFlight
+	}
+	h.inFlight[id] = true
+	return nil
+}
+
+// finish ends the dispatch of an event ID. Only handled events are remembered;
+// a failed one is accepted again when the API retries it.
+func (h *Handler) finish(id string, handled bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	delete(h.inFlight, id)
+	if handled {
+		h.seen[id] = h.now()
+	}
+}
+
+// Sign returns the signature header value for payload signed at t.
+func Sign(payload []byte, secret string, t time.Time) string {
+	ts := strconv.FormatInt(t.Unix(), 10)
+	return "t=" + ts + ",v1=" + computeSignature(payload, secret, ts)
+}
+
+// Verify checks a signature header against payload and secret. A tolerance
+// of zero uses DefaultTolerance.
+func Verify(payload []byte, header, secret string, tolerance time.Duration) error {
+	if tolerance == 0 {
+		tolerance = DefaultTolerance
+	}
+	return verify(payload, header, []string{secret}, tolerance, time.Now())
+}
+
+func verify(payload []byte, header string, secrets []string, tolerance time.Duration, now time.Time) error {
+	if header == "" {
+		return ErrMissingSignature
+	}
+
+	var ts string
+	var sigs []string
+	for _, part := range strings.Split(header, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "t":
+			ts = value
+		case "v1":
+			sigs = append(sigs, value)
+		}
+	}
+	if ts == "" || len(sigs) == 0 {
+		return ErrInvalidSignature
+	}
+
+	unix, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	age := now.Sub(time.Unix(unix, 0))
+	if age > tolerance || age < -tolerance {
+		return ErrTimestampExpired
+	}
+
+	for _, secret := range secrets {
+		expected := computeSignature(payload, secret, ts)
+		for _, sig := range sigs {
+			if hmac.Equal([]byte(expected), []byte(sig)) {
+				return nil
+			}
+		}
+	}
+	return ErrInvalidSignature
+}
+
+func computeSignature(payload []byte, secret, ts string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(ts))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
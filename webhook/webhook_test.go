@@ -0,0 +1,191 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	taskforceai "github.com/ClayWarren/taskforceai-sdk-go"
+)
+
+func newDelivery(t *testing.T, body, secret string, at time.Time) *http.Request {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPost, "/hooks", bytes.NewBufferString(body))
+	req.Header.Set(SignatureHeader, Sign([]byte(body), secret, at))
+	return req
+}
+
+func TestHandler_Dispatch(t *testing.T) {
+	var completed, failed string
+	h := NewHandler(HandlerOptions{
+		Secret: "whsec",
+		OnCompleted: func(ctx context.Context, task taskforceai.TaskStatus) error {
+			completed = *task.Result
+			return nil
+		},
+		OnFailed: func(ctx context.Context, task taskforceai.TaskStatus) error {
+			failed = *task.Error
+			return nil
+		},
+	})
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, newDelivery(t, `{"id":"evt_1","type":"task.completed","data":{"taskId":"t1","status":"completed","result":"done"}}`, "whsec", time.Now()))
+	if rec.Code != http.StatusOK || completed != "done" {
+		t.Errorf("expected completed dispatch, got status %d result %q", rec.Code, completed)
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, newDelivery(t, `{"id":"evt_2","type":"task.failed","data":{"taskId":"t2","status":"failed","error":"boom"}}`, "whsec", time.Now()))
+	if rec.Code != http.StatusOK || failed != "boom" {
+		t.Errorf("expected failed dispatch, got status %d error %q", rec.Code, failed)
+	}
+}
+
+func TestHandler_Rejections(t *testing.T) {
+	h := NewHandler(HandlerOptions{Secret: "whsec", PreviousSecrets: []string{"old"}})
+	body := `{"id":"evt_1","type":"task.completed","data":{"taskId":"t1","status":"completed"}}`
+
+	// 1. Wrong secret
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, newDelivery(t, body, "wrong", time.Now()))
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401 for bad signature, got %d", rec.Code)
+	}
+
+	// 2. Stale timestamp
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, newDelivery(t, body, "whsec", time.Now().Add(-time.Hour)))
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401 for stale timestamp, got %d", rec.Code)
+	}
+
+	// 3. Missing header
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/hooks", bytes.NewBufferString(body)))
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401 for missing signature, got %d", rec.Code)
+	}
+
+	// 4. Wrong method
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/hooks", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected 405, got %d", rec.Code)
+	}
+
+	// 5. Previous secret still accepted
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, newDelivery(t, body, "old", time.Now()))
+	if rec.Code != http.StatusOK {
+		t.Errorf("expected previous secret to verify, got %d", rec.Code)
+	}
+
+	// 6. Signed event without an ID
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, newDelivery(t, `{"type":"task.completed","data":{"taskId":"t1"}}`, "whsec", time.Now()))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for missing event ID, got %d", rec.Code)
+	}
+}
+
+func TestHandler_Replay(t *testing.T) {
+	calls := 0
+	fail := true
+	h := NewHandler(HandlerOptions{
+		Secret: "whsec",
+		OnCompleted: func(ctx context.Context, task taskforceai.TaskStatus) error {
+			calls++
+			if fail {
+				return errors.New("temporary")
+			}
+			return nil
+		},
+	})
+	body := `{"id":"evt_1","type":"task.completed","data":{"taskId":"t1","status":"completed"}}`
+
+	// A failed delivery must be retryable.
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, newDelivery(t, body, "whsec", time.Now()))
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("expected 500 from handler error, got %d", rec.Code)
+	}
+
+	fail = false
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, newDelivery(t, body, "whsec", time.Now()))
+	if rec.Code != http.StatusOK {
+		t.Errorf("expected retry to succeed, got %d", rec.Code)
+	}
+
+	// A replay of a handled delivery is acknowledged without dispatch.
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, newDelivery(t, body, "whsec", time.Now()))
+	if rec.Code != http.StatusOK || calls != 2 {
+		t.Errorf("expected replay to be acknowledged without dispatch, got status %d calls %d", rec.Code, calls)
+	}
+}
+
+func TestHandler_RetryWhileInFlight(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan error)
+	calls := 0
+	h := NewHandler(HandlerOptions{
+		Secret: "whsec",
+		OnCompleted: func(ctx context.Context, task taskforceai.TaskStatus) error {
+			calls++
+			if calls == 1 {
+				close(started)
+				return <-release
+			}
+			return nil
+		},
+	})
+	body := `{"id":"evt_1","type":"task.completed","data":{"taskId":"t1","status":"completed"}}`
+
+	first := httptest.NewRecorder()
+	done := make(chan struct{})
+	go func() {
+		h.ServeHTTP(first, newDelivery(t, body, "whsec", time.Now()))
+		close(done)
+	}()
+	<-started
+
+	// A retry during the first dispatch must not be acknowledged.
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, newDelivery(t, body, "whsec", time.Now()))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected 503 while in flight, got %d", rec.Code)
+	}
+
+	release <- errors.New("temporary")
+	<-done
+	if first.Code != http.StatusInternalServerError {
+		t.Errorf("expected 500 from first delivery, got %d", first.Code)
+	}
+
+	// The event was not lost: the next retry is dispatched.
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, newDelivery(t, body, "whsec", time.Now()))
+	if rec.Code != http.StatusOK || calls != 2 {
+		t.Errorf("expected retry to be dispatched, got status %d calls %d", rec.Code, calls)
+	}
+}
+
+func TestVerify(t *testing.T) {
+	payload := []byte(`{}`)
+	header := Sign(payload, "whsec", time.Now())
+	if err := Verify(payload, header, "whsec", 0); err != nil {
+		t.Errorf("expected valid signature, got %v", err)
+	}
+	if err := Verify([]byte(`{"x":1}`), header, "whsec", 0); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("expected ErrInvalidSignature for tampered payload, got %v", err)
+	}
+	if err := Verify(payload, "garbage", "whsec", 0); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("expected ErrInvalidSignature for malformed header, got %v", err)
+	}
+}
@@ -0,0 +1,176 @@
+package taskforceai
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// WebhookEventType identifies an event a webhook subscription receives.
+type WebhookEventType string
+
+const (
+	WebhookEventTaskCompleted WebhookEventType = "task.completed"
+	WebhookEventTaskFailed    WebhookEventType = "task.failed"
+)
+
+// Webhook represents an account-level webhook subscription.
+type Webhook struct {
+	ID          string             `json:"id"`
+	URL         string             `json:"url"`
+	Description string             `json:"description,omitempty"`
+	Events      []WebhookEventType `json:"events"`
+	Enabled     bool               `json:"enabled"`
+	Secret      string             `json:"secret,omitempty"` // only returned on create and rotate
+	CreatedAt   time.Time          `json:"created_at"`
+	UpdatedAt   time.Time          `json:"updated_at"`
+}
+
+// CreateWebhookOptions contains options for creating a webhook.
+type CreateWebhookOptions struct {
+	URL         string             `json:"url"`
+	Description string             `json:"description,omitempty"`
+	Events      []WebhookEventType `json:"events,omitempty"` // empty subscribes to all events
+}
+
+// UpdateWebhookOptions contains the fields to change on a webhook. Nil fields
+// are left unchanged; Events pointing to an empty slice subscribes the
+// webhook to all events again.
+type UpdateWebhookOptions struct {
+	URL         *string             `json:"url,omitempty"`
+	Description *string             `json:"description,omitempty"`
+	Events      *[]WebhookEventType `json:"events,omitempty"`
+	Enabled     *bool               `json:"enabled,omitempty"`
+}
+
+// WebhookListResponse contains a list of webhooks.
+type WebhookListResponse struct {
+	Webhooks []Webhook `json:"webhooks"`
+	Total    int       `json:"total"`
+}
+
+// WebhookDelivery records one attempt to deliver an event to a webhook.
+type WebhookDelivery struct {
+	ID         string           `json:"id"`
+	WebhookID  string           `json:"webhook_id"`
+	EventID    string           `json:"event_id"`
+	EventType  WebhookEventType `json:"event_type"`
+	StatusCode int              `json:"status_code"`
+	Success    bool             `json:"success"`
+	Attempt    int              `json:"attempt"`
+	Error      string           `json:"error,omitempty"`
+	CreatedAt  time.Time        `json:"created_at"`
+}
+
+// WebhookDeliveryListResponse contains a list of webhook deliveries.
+type WebhookDeliveryListResponse struct {
+	Deliveries []WebhookDelivery `json:"deliveries"`
+	Total      int               `json:"total"`
+}
+
+// CreateWebhook subscribes a URL to webhook events. The returned Webhook
+// carries the signing secret, which is not returned again.
+func (c *Client) CreateWebhook(ctx context.Context, opts CreateWebhookOptions) (*Webhook, error) {
+	if opts.URL == "" {
+		return nil, fmt.Errorf("url is required")
+	}
+
+	body := map[string]interface{}{
+		"url": opts.URL,
+	}
+	if opts.Description != "" {
+		body["description"] = opts.Description
+	}
+	if len(opts.Events) > 0 {
+		body["events"] = opts.Events
+	}
+
+	webhook, err := do[Webhook](ctx, c, "POST", "/webhooks", body, "create webhook")
+	if err != nil {
+		return nil, err
+	}
+
+	return &webhook, nil
+}
+
+// ListWebhooks retrieves a list of webhooks.
+func (c *Client) ListWebhooks(ctx context.Context, limit, offset int) (*WebhookListResponse, error) {
+	path := fmt.Sprintf("/webhooks?limit=%d&offset=%d", limit, offset)
+
+	result, err := do[WebhookListResponse](ctx, c, "GET", path, nil, "list webhooks")
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// GetWebhook retrieves a specific webhook by ID.
+func (c *Client) GetWebhook(ctx context.Context, webhookID string) (*Webhook, error) {
+	path := "/webhooks/" + webhookID
+
+	webhook, err := do[Webhook](ctx, c, "GET", path, nil, "get webhook")
+	if err != nil {
+		return nil, err
+	}
+
+	return &webhook, nil
+}
+
+// UpdateWebhook changes the URL, description, event filter or enabled state
+// of a webhook.
+func (c *Client) UpdateWebhook(ctx context.Context, webhookID string, opts UpdateWebhookOptions) (*Webhook, error) {
+	path := "/webhooks/" + webhookID
+
+	webhook, err := do[Webhook](ctx, c, "PATCH", path, opts, "update webhook")
+	if err != nil {
+		return nil, err
+	}
+
+	return &webhook, nil
+}
+
+// DeleteWebhook deletes a webhook by ID.
+func (c *Client) DeleteWebhook(ctx context.Context, webhookID string) error {
+	path := "/webhooks/" + webhookID
+
+	return doNoContent(ctx, c, "DELETE", path, nil, "delete webhook")
+}
+
+// RotateWebhookSecret issues a new signing secret for a webhook. The returned
+// Webhook carries the new secret; keep accepting the old one (see
+// webhook.HandlerOptions.PreviousSecrets) until in-flight deliveries drain.
+func (c *Client) RotateWebhookSecret(ctx context.Context, webhookID string) (*Webhook, error) {
+	path := "/webhooks/" + webhookID + "/rotate-secret"
+
+	webhook, err := do[Webhook](ctx, c, "POST", path, nil, "rotate webhook secret")
+	if err != nil {
+		return nil, err
+	}
+
+	return &webhook, nil
+}
+
+// ListWebhookDeliveries retrieves recent delivery attempts for a webhook.
+func (c *Client) ListWebhookDeliveries(ctx context.Context, webhookID string, limit, offset int) (*WebhookDeliveryListResponse, error) {
+	path := fmt.Sprintf("/webhooks/%s/deliveries?limit=%d&offset=%d", webhookID, limit, offset)
+
+	result, err := do[WebhookDeliveryListResponse](ctx, c, "GET", path, nil, "list webhook deliveries")
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// RedeliverWebhook queues a new attempt of a previous delivery.
+func (c *Client) RedeliverWebhook(ctx context.Context, webhookID, deliveryID string) (*WebhookDelivery, error) {
+	path := "/webhooks/" + webhookID + "/deliveries/" + deliveryID + "/redeliver"
+
+	delivery, err := do[WebhookDelivery](ctx, c, "POST", path, nil, "redeliver webhook")
+	if err != nil {
+		return nil, err
+	}
+
+	return &delivery, nil
+}
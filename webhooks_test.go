@@ -0,0 +1,117 @@
+package taskforceai
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClient_CreateWebhook(t *testing.T) {
+	var got map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || r.URL.Path != "/webhooks" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		_ = json.NewEncoder(w).Encode(Webhook{ID: "wh-1", URL: "https://example.com/hook", Secret: "whsec", Enabled: true})
+	}))
+	defer server.Close()
+	client := NewClient(TaskForceAIOptions{BaseURL: server.URL})
+
+	webhook, err := client.CreateWebhook(context.Background(), CreateWebhookOptions{
+		URL:    "https://example.com/hook",
+		Events: []WebhookEventType{WebhookEventTaskFailed},
+	})
+	if err != nil {
+		t.Fatalf("CreateWebhook failed: %v", err)
+	}
+	if webhook.ID != "wh-1" || webhook.Secret != "whsec" {
+		t.Errorf("unexpected webhook %+v", webhook)
+	}
+	events, _ := got["events"].([]any)
+	if got["url"] != "https://example.com/hook" || len(events) != 1 || events[0] != "task.failed" {
+		t.Errorf("unexpected body %v", got)
+	}
+	if _, ok := got["description"]; ok {
+		t.Errorf("expected empty description to be omitted, got %v", got)
+	}
+
+	if _, err := client.CreateWebhook(context.Background(), CreateWebhookOptions{}); err == nil {
+		t.Error("expected error for missing url")
+	}
+}
+
+func TestClient_ListWebhooks(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" || r.URL.Path != "/webhooks" || r.URL.RawQuery != "limit=10&offset=20" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+		_ = json.NewEncoder(w).Encode(WebhookListResponse{Webhooks: []Webhook{{ID: "wh-1"}, {ID: "wh-2"}}, Total: 22})
+	}))
+	defer server.Close()
+	client := NewClient(TaskForceAIOptions{BaseURL: server.URL})
+
+	result, err := client.ListWebhooks(context.Background(), 10, 20)
+	if err != nil {
+		t.Fatalf("ListWebhooks failed: %v", err)
+	}
+	if len(result.Webhooks) != 2 || result.Total != 22 {
+		t.Errorf("unexpected result %+v", result)
+	}
+}
+
+func TestClient_UpdateWebhook(t *testing.T) {
+	var got map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "PATCH" || r.URL.Path != "/webhooks/wh-1" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		got = nil
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		_ = json.NewEncoder(w).Encode(Webhook{ID: "wh-1"})
+	}))
+	defer server.Close()
+	client := NewClient(TaskForceAIOptions{BaseURL: server.URL})
+
+	enabled := false
+	if _, err := client.UpdateWebhook(context.Background(), "wh-1", UpdateWebhookOptions{Enabled: &enabled}); err != nil {
+		t.Fatalf("UpdateWebhook failed: %v", err)
+	}
+	if len(got) != 1 || got["enabled"] != false {
+		t.Errorf("expected only enabled to be sent, got %v", got)
+	}
+
+	// An empty event list resets the filter to all events.
+	all := []WebhookEventType{}
+	if _, err := client.UpdateWebhook(context.Background(), "wh-1", UpdateWebhookOptions{Events: &all}); err != nil {
+		t.Fatalf("UpdateWebhook failed: %v", err)
+	}
+	if events, ok := got["events"].([]any); !ok || len(events) != 0 || len(got) != 1 {
+		t.Errorf("expected empty events to be sent, got %v", got)
+	}
+}
+
+func TestClient_DeleteWebhook(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "DELETE" {
+			t.Errorf("unexpected method %s", r.Method)
+		}
+		if r.URL.Path != "/webhooks/wh-1" {
+			http.Error(w, `{"error": "webhook not found"}`, http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+	client := NewClient(TaskForceAIOptions{BaseURL: server.URL})
+
+	if err := client.DeleteWebhook(context.Background(), "wh-1"); err != nil {
+		t.Fatalf("DeleteWebhook failed: %v", err)
+	}
+	err := client.DeleteWebhook(context.Background(), "wh-missing")
+	if !isStatus(err, http.StatusNotFound) || err.Error() != "failed to delete webhook: status 404: webhook not found" {
+		t.Errorf("expected 404 error, got %v", err)
+	}
+}